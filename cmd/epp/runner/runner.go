@@ -332,6 +332,9 @@ func (r *Runner) Run(ctx context.Context) error {
 		Director:                         director,
 		SaturationDetector:               saturationDetector,
 		UseExperimentalDatalayerV2:       r.featureGates[datalayer.ExperimentalDatalayerFeatureGate], // pluggable data layer feature flag
+		LogRawBodies:                     opts.LogRawBodies,
+		RequestIDFormat:                  opts.RequestIDFormat,
+		RejectInvalidRequestIDs:          opts.RejectInvalidRequestIDs,
 	}
 	if err := serverRunner.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Failed to setup EPP controllers")
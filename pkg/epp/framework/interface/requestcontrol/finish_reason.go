@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestcontrol
+
+import "strings"
+
+// OpenAI finish reasons, the canonical set NormalizeFinishReason maps backend-specific values to.
+const (
+	FinishReasonStop          = "stop"
+	FinishReasonLength        = "length"
+	FinishReasonToolCalls     = "tool_calls"
+	FinishReasonContentFilter = "content_filter"
+)
+
+// finishReasonAliases maps finish reasons reported by non-OpenAI backends, lower-cased, to their OpenAI equivalent.
+var finishReasonAliases = map[string]string{
+	// TGI
+	"eos_token":     FinishReasonStop,
+	"stop_sequence": FinishReasonStop,
+	// Anthropic
+	"end_turn":   FinishReasonStop,
+	"max_tokens": FinishReasonLength,
+	"tool_use":   FinishReasonToolCalls,
+	"refusal":    FinishReasonContentFilter,
+	// Gemini, whose upper-case values are lower-cased before lookup
+	"safety":             FinishReasonContentFilter,
+	"recitation":         FinishReasonContentFilter,
+	"blocklist":          FinishReasonContentFilter,
+	"prohibited_content": FinishReasonContentFilter,
+	"spii":               FinishReasonContentFilter,
+	// Legacy OpenAI function calling
+	"function_call": FinishReasonToolCalls,
+}
+
+// NormalizeFinishReason maps a backend-specific finish reason to the OpenAI canonical set: "stop", "length",
+// "tool_calls" or "content_filter". Matching is case-insensitive. Values with no canonical equivalent, such as
+// vLLM's "abort", are returned lower-cased so they are still reported.
+func NormalizeFinishReason(raw string) string {
+	reason := strings.ToLower(strings.TrimSpace(raw))
+	if alias, ok := finishReasonAliases[reason]; ok {
+		return alias
+	}
+	return reason
+}
+
+// IsToolCall reports whether the response ended because the model invoked tools.
+func (r *Response) IsToolCall() bool {
+	return r != nil && r.FinishReason == FinishReasonToolCalls
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestcontrol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseIsToolCall(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response *Response
+		want     bool
+	}{
+		{name: "tool calls", response: &Response{FinishReason: "tool_calls"}, want: true},
+		{name: "stop", response: &Response{FinishReason: "stop"}},
+		{name: "no finish reason", response: &Response{}},
+		{name: "nil response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.response.IsToolCall())
+		})
+	}
+}
+
+func TestNormalizeFinishReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "empty", raw: "", want: ""},
+		// OpenAI and vLLM
+		{name: "openai stop", raw: "stop", want: FinishReasonStop},
+		{name: "openai length", raw: "length", want: FinishReasonLength},
+		{name: "openai tool calls", raw: "tool_calls", want: FinishReasonToolCalls},
+		{name: "openai content filter", raw: "content_filter", want: FinishReasonContentFilter},
+		{name: "openai legacy function call", raw: "function_call", want: FinishReasonToolCalls},
+		{name: "vLLM abort has no canonical equivalent", raw: "abort", want: "abort"},
+		// TGI
+		{name: "tgi eos token", raw: "eos_token", want: FinishReasonStop},
+		{name: "tgi stop sequence", raw: "stop_sequence", want: FinishReasonStop},
+		{name: "tgi length", raw: "length", want: FinishReasonLength},
+		// Anthropic
+		{name: "anthropic end turn", raw: "end_turn", want: FinishReasonStop},
+		{name: "anthropic max tokens", raw: "max_tokens", want: FinishReasonLength},
+		{name: "anthropic tool use", raw: "tool_use", want: FinishReasonToolCalls},
+		{name: "anthropic refusal", raw: "refusal", want: FinishReasonContentFilter},
+		// Gemini
+		{name: "gemini stop", raw: "STOP", want: FinishReasonStop},
+		{name: "gemini max tokens", raw: "MAX_TOKENS", want: FinishReasonLength},
+		{name: "gemini safety", raw: "SAFETY", want: FinishReasonContentFilter},
+		{name: "surrounding whitespace", raw: " stop\n", want: FinishReasonStop},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, NormalizeFinishReason(tt.raw))
+		})
+	}
+}
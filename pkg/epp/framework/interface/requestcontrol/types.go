@@ -17,6 +17,8 @@ limitations under the License.
 package requestcontrol
 
 import (
+	"fmt"
+
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -38,18 +40,54 @@ type Response struct {
 	ReqMetadata map[string]any
 	// Token usage counts parsed from the response body.
 	Usage Usage
+	// ObjectType is the OpenAI object type declared by the response body, such as "chat.completion",
+	// "text_completion" or "error". Empty when the body does not declare one.
+	ObjectType string
+	// FinishReason is the reason the model stopped generating, such as "stop", "length" or "tool_calls", normalized by
+	// NormalizeFinishReason. Empty until the response reports one.
+	FinishReason string
+	// Model is the model that served the response, as reported by the model server. Streams use the first chunk that
+	// reports one. Empty when the response does not report one.
+	Model string
+	// ToolCallsDetected indicates that the model invoked tools, through tool call deltas or messages, or a
+	// "tool_calls" finish reason.
+	ToolCallsDetected bool
+	// ReasoningContentPresent indicates that the model server returned reasoning content separately from the answer,
+	// as "reasoning_content" deltas or messages.
+	ReasoningContentPresent bool
+	// ModelServerError is the error object the model server returned in place of a response, which is relayed to the
+	// client as is. Nil for successful responses.
+	ModelServerError *ModelServerError
 	// DynamicMetadata is a map of metadata that can be passed to the Envoy. It is populated into the dynamic
 	// metadata when processing ProcessingResponse_RequestHeaders.
 	DynamicMetadata *structpb.Struct
 }
 
+// ModelServerError is an OpenAI-style error object returned by the model server in place of a response.
+type ModelServerError struct {
+	Message string
+	Type    string
+	Code    string
+}
+
+func (e *ModelServerError) Error() string {
+	return fmt.Sprintf("model server returned an error (type: %q, code: %q): %s", e.Type, e.Code, e.Message)
+}
+
 type Usage struct {
-	PromptTokens       int                 `json:"prompt_tokens"`
-	CompletionTokens   int                 `json:"completion_tokens"`
-	TotalTokens        int                 `json:"total_tokens"`
-	PromptTokenDetails *PromptTokenDetails `json:"prompt_token_details,omitempty"`
+	PromptTokens           int                     `json:"prompt_tokens"`
+	CompletionTokens       int                     `json:"completion_tokens"`
+	TotalTokens            int                     `json:"total_tokens"`
+	PromptTokenDetails     *PromptTokenDetails     `json:"prompt_token_details,omitempty"`
+	CompletionTokenDetails *CompletionTokenDetails `json:"completion_tokens_details,omitempty"`
 }
 
 type PromptTokenDetails struct {
 	CachedTokens int `json:"cached_tokens"`
 }
+
+// CompletionTokenDetails breaks down the completion tokens reported by the model server.
+// Reasoning tokens are a subset of CompletionTokens and are not added to TotalTokens separately.
+type CompletionTokenDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
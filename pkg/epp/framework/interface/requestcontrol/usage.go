@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestcontrol
+
+// UsageDiff holds the per-field difference between two Usage values, computed as b - a by DiffUsage.
+type UsageDiff struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	CachedTokens     int
+	ReasoningTokens  int
+}
+
+// IsZero reports whether the compared usages agree on every field.
+func (d UsageDiff) IsZero() bool {
+	return d == UsageDiff{}
+}
+
+// DiffUsage returns the per-field difference b - a, e.g. to reconcile client-reported against model
+// server-reported usage. A nil Usage is treated as having all counts zero.
+func DiffUsage(a, b *Usage) UsageDiff {
+	return UsageDiff{
+		PromptTokens:     b.promptTokens() - a.promptTokens(),
+		CompletionTokens: b.completionTokens() - a.completionTokens(),
+		TotalTokens:      b.totalTokens() - a.totalTokens(),
+		CachedTokens:     b.cachedTokens() - a.cachedTokens(),
+		ReasoningTokens:  b.reasoningTokens() - a.reasoningTokens(),
+	}
+}
+
+// MergeUsage returns the sum of two partial Usage values, e.g. from separate chunks of a response. TotalTokens is
+// recomputed from the summed prompt and completion tokens rather than summed, and token details are only set when
+// either input carries them. A nil Usage contributes nothing, and MergeUsage returns nil if both are nil. The inputs
+// are not modified.
+func MergeUsage(a, b *Usage) *Usage {
+	if a == nil && b == nil {
+		return nil
+	}
+	merged := &Usage{
+		PromptTokens:     a.promptTokens() + b.promptTokens(),
+		CompletionTokens: a.completionTokens() + b.completionTokens(),
+	}
+	merged.TotalTokens = merged.PromptTokens + merged.CompletionTokens
+	if a.hasPromptTokenDetails() || b.hasPromptTokenDetails() {
+		merged.PromptTokenDetails = &PromptTokenDetails{CachedTokens: a.cachedTokens() + b.cachedTokens()}
+	}
+	if a.hasCompletionTokenDetails() || b.hasCompletionTokenDetails() {
+		merged.CompletionTokenDetails = &CompletionTokenDetails{ReasoningTokens: a.reasoningTokens() + b.reasoningTokens()}
+	}
+	return merged
+}
+
+func (u *Usage) hasPromptTokenDetails() bool {
+	return u != nil && u.PromptTokenDetails != nil
+}
+
+func (u *Usage) hasCompletionTokenDetails() bool {
+	return u != nil && u.CompletionTokenDetails != nil
+}
+
+func (u *Usage) promptTokens() int {
+	if u == nil {
+		return 0
+	}
+	return u.PromptTokens
+}
+
+func (u *Usage) completionTokens() int {
+	if u == nil {
+		return 0
+	}
+	return u.CompletionTokens
+}
+
+func (u *Usage) totalTokens() int {
+	if u == nil {
+		return 0
+	}
+	return u.TotalTokens
+}
+
+func (u *Usage) cachedTokens() int {
+	if u == nil || u.PromptTokenDetails == nil {
+		return 0
+	}
+	return u.PromptTokenDetails.CachedTokens
+}
+
+func (u *Usage) reasoningTokens() int {
+	if u == nil || u.CompletionTokenDetails == nil {
+		return 0
+	}
+	return u.CompletionTokenDetails.ReasoningTokens
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestcontrol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffUsage(t *testing.T) {
+	t.Parallel()
+
+	clientReported := &Usage{
+		PromptTokens:       100,
+		CompletionTokens:   50,
+		TotalTokens:        150,
+		PromptTokenDetails: &PromptTokenDetails{CachedTokens: 40},
+	}
+	serverReported := &Usage{
+		PromptTokens:           110,
+		CompletionTokens:       45,
+		TotalTokens:            155,
+		PromptTokenDetails:     &PromptTokenDetails{CachedTokens: 64},
+		CompletionTokenDetails: &CompletionTokenDetails{ReasoningTokens: 20},
+	}
+
+	tests := []struct {
+		name string
+		a    *Usage
+		b    *Usage
+		want UsageDiff
+	}{
+		{
+			name: "both nil",
+			want: UsageDiff{},
+		},
+		{
+			name: "identical usages",
+			a:    clientReported,
+			b:    clientReported,
+			want: UsageDiff{},
+		},
+		{
+			name: "differing usages",
+			a:    clientReported,
+			b:    serverReported,
+			want: UsageDiff{PromptTokens: 10, CompletionTokens: -5, TotalTokens: 5, CachedTokens: 24, ReasoningTokens: 20},
+		},
+		{
+			name: "nil a is treated as zero",
+			b:    serverReported,
+			want: UsageDiff{PromptTokens: 110, CompletionTokens: 45, TotalTokens: 155, CachedTokens: 64, ReasoningTokens: 20},
+		},
+		{
+			name: "nil b is treated as zero",
+			a:    clientReported,
+			want: UsageDiff{PromptTokens: -100, CompletionTokens: -50, TotalTokens: -150, CachedTokens: -40},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := DiffUsage(tc.a, tc.b)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.want == UsageDiff{}, got.IsZero())
+		})
+	}
+}
+
+func TestMergeUsage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    *Usage
+		b    *Usage
+		want *Usage
+	}{
+		{
+			name: "both nil",
+			want: nil,
+		},
+		{
+			name: "nil first",
+			b:    &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			want: &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		},
+		{
+			name: "nil second",
+			a:    &Usage{PromptTokens: 10, PromptTokenDetails: &PromptTokenDetails{CachedTokens: 4}},
+			want: &Usage{PromptTokens: 10, TotalTokens: 10, PromptTokenDetails: &PromptTokenDetails{CachedTokens: 4}},
+		},
+		{
+			name: "overlapping fields are summed",
+			a: &Usage{
+				PromptTokens:       100,
+				CompletionTokens:   20,
+				TotalTokens:        120,
+				PromptTokenDetails: &PromptTokenDetails{CachedTokens: 64},
+			},
+			b: &Usage{
+				PromptTokens:           0,
+				CompletionTokens:       30,
+				TotalTokens:            30,
+				PromptTokenDetails:     &PromptTokenDetails{CachedTokens: 0},
+				CompletionTokenDetails: &CompletionTokenDetails{ReasoningTokens: 12},
+			},
+			want: &Usage{
+				PromptTokens:           100,
+				CompletionTokens:       50,
+				TotalTokens:            150,
+				PromptTokenDetails:     &PromptTokenDetails{CachedTokens: 64},
+				CompletionTokenDetails: &CompletionTokenDetails{ReasoningTokens: 12},
+			},
+		},
+		{
+			name: "total tokens are recomputed",
+			a:    &Usage{PromptTokens: 3, CompletionTokens: 4, TotalTokens: 99},
+			b:    &Usage{CompletionTokens: 1},
+			want: &Usage{PromptTokens: 3, CompletionTokens: 5, TotalTokens: 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, MergeUsage(tt.a, tt.b))
+		})
+	}
+}
+
+func TestMergeUsageDoesNotModifyInputs(t *testing.T) {
+	t.Parallel()
+
+	a := &Usage{PromptTokens: 1, PromptTokenDetails: &PromptTokenDetails{CachedTokens: 1}}
+	b := &Usage{PromptTokens: 2, PromptTokenDetails: &PromptTokenDetails{CachedTokens: 2}}
+
+	merged := MergeUsage(a, b)
+	merged.PromptTokenDetails.CachedTokens = 100
+
+	assert.Equal(t, &Usage{PromptTokens: 1, PromptTokenDetails: &PromptTokenDetails{CachedTokens: 1}}, a)
+	assert.Equal(t, &Usage{PromptTokens: 2, PromptTokenDetails: &PromptTokenDetails{CachedTokens: 2}}, b)
+}
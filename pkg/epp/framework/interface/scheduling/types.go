@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	fwkdl "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/datalayer"
 )
@@ -53,7 +54,7 @@ func (r *LLMRequest) String() string {
 		return nilString
 	}
 
-	return fmt.Sprintf("RequestID: %s, TargetModel: %s, Body: %s, Headers: %v",
+	return fmt.Sprintf("RequestID: %s, TargetModel: %s, Body: %v, Headers: %v",
 		r.RequestId, r.TargetModel, r.Body, r.Headers)
 }
 
@@ -69,6 +70,12 @@ type LLMRequestBody struct {
 	Responses *ResponsesRequest `json:"responses,omitempty"`
 	// ConversationsRequest is the representation of the OpenAI /v1/conversations request body.
 	Conversations *ConversationsRequest `json:"conversations,omitempty"`
+	// EstimatedPromptTokens is an estimate of the prompt size in tokens, stored by the first scheduling plugin that
+	// asks for one through the util/request estimation helpers. It is zero until an estimate is computed.
+	EstimatedPromptTokens int `json:"estimated_prompt_tokens,omitempty"`
+	// RequestTimeout is the timeout the client asked for via the x-request-timeout-ms header, for routing and retry
+	// logic to honor. It is zero when the client did not ask for one.
+	RequestTimeout time.Duration `json:"request_timeout,omitempty"`
 }
 
 func (r *LLMRequestBody) CacheSalt() string {
@@ -93,17 +100,60 @@ func (r *LLMRequestBody) CacheSalt() string {
 // API spec.
 type CompletionsRequest struct {
 	// Prompt is the prompt that was sent in the request body.
+	// It is empty for a batch request, whose prompts are held in Prompts.
 	Prompt string `json:"prompt,omitempty"`
+	// Prompts holds the individual prompts of a batch request, where the request body carries an array of prompts
+	// that are generated independently. It is nil when the request body carries a single prompt.
+	Prompts []string `json:"-"`
 	// CacheSalt is an optional request parameter to isolate prefix caches for security reasons.
 	CacheSalt string `json:"cache_salt,omitempty"`
 }
 
+// UnmarshalJSON accepts the prompt either as a single string or as an array of strings.
+func (r *CompletionsRequest) UnmarshalJSON(data []byte) error {
+	type completionsRequest CompletionsRequest
+	aux := struct {
+		*completionsRequest
+		Prompt json.RawMessage `json:"prompt,omitempty"`
+	}{completionsRequest: (*completionsRequest)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Prompt) == 0 {
+		return nil
+	}
+
+	var prompt string
+	if err := json.Unmarshal(aux.Prompt, &prompt); err == nil {
+		r.Prompt = prompt
+		return nil
+	}
+	var prompts []string
+	if err := json.Unmarshal(aux.Prompt, &prompts); err == nil {
+		r.Prompts = prompts
+		return nil
+	}
+	return errors.New("prompt format not supported")
+}
+
+// BatchSize returns the number of independent prompts carried by the request.
+func (r *CompletionsRequest) BatchSize() int {
+	if r.Prompts != nil {
+		return len(r.Prompts)
+	}
+	return 1
+}
+
 func (r *CompletionsRequest) String() string {
 	if r == nil {
 		return nilString
 	}
 
-	return fmt.Sprintf("{PromptLength: %d}", len(r.Prompt))
+	promptLength := len(r.Prompt)
+	for _, prompt := range r.Prompts {
+		promptLength += len(prompt)
+	}
+	return fmt.Sprintf("{PromptLength: %d, BatchSize: %d}", promptLength, r.BatchSize())
 }
 
 // ChatCompletionsRequest is a structured representation of the fields we parse out of the v1/chat/completions
@@ -114,6 +164,9 @@ type ChatCompletionsRequest struct {
 	/* parameters from the official OpenAI chat-completions API */
 	Messages []Message     `json:"messages,omitempty"`
 	Tools    []interface{} `json:"tools,omitempty"`
+	// ParallelToolCalls reports whether the model may issue several tool calls in a single turn. It is nil when the
+	// request leaves it to the model server default.
+	ParallelToolCalls *bool `json:"parallel_tool_calls,omitempty"`
 	/* parameters from the HuggingFace transformers chat-templates API */
 	Documents                 []interface{}          `json:"documents,omitempty"`
 	ChatTemplate              string                 `json:"chat_template,omitempty"`
@@ -189,6 +242,11 @@ type Message struct {
 	Role string `json:"role,omitempty"`
 	// Content defines text of this message
 	Content Content `json:"content,omitempty"`
+	// ToolCalls are the tool calls generated by the model in an assistant message
+	ToolCalls []interface{} `json:"tool_calls,omitempty"`
+	// FunctionCall is the function call generated by the model in an assistant message, from the deprecated
+	// function-calling API that predates tool calls
+	FunctionCall interface{} `json:"function_call,omitempty"`
 }
 
 type Content struct {
@@ -246,6 +304,8 @@ func (mc Content) MarshalJSON() ([]byte, error) {
 	return json.Marshal("")
 }
 
+// PlainText returns the text of the content. The text parts of array-form content are concatenated as is, without
+// a separator between them, and non-text parts are skipped.
 func (mc Content) PlainText() string {
 	if mc.Raw != "" {
 		return mc.Raw
@@ -254,7 +314,6 @@ func (mc Content) PlainText() string {
 	for _, block := range mc.Structured {
 		if block.Type == "text" {
 			sb.WriteString(block.Text)
-			sb.WriteString(" ")
 		}
 	}
 	return sb.String()
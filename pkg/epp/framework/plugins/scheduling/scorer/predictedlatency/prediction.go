@@ -41,6 +41,22 @@ type endpointPredictionResult struct {
 	PrefixCacheScore float64 // Prefix cache score for the pod
 }
 
+// inputPrompt returns the prompt whose length drives latency predictions for a completions request. The model server
+// prefills the prompts of a batch as independent sequences, so the longest one stands in for the batch.
+func inputPrompt(request *schedulingtypes.LLMRequest) string {
+	completions := request.Body.Completions
+	if completions.Prompts == nil {
+		return completions.Prompt
+	}
+	longest := ""
+	for _, prompt := range completions.Prompts {
+		if len(prompt) > len(longest) {
+			longest = prompt
+		}
+	}
+	return longest
+}
+
 // generatePredictions creates prediction results for all candidate pods
 func (s *PredictedLatency) generatePredictions(ctx context.Context, request *schedulingtypes.LLMRequest, predictedLatencyCtx *predictedLatencyCtx, candidateEndpoints []schedulingtypes.Endpoint) ([]endpointPredictionResult, error) {
 	logger := log.FromContext(ctx)
@@ -61,7 +77,7 @@ func (s *PredictedLatency) generatePredictions(ctx context.Context, request *sch
 		logger.V(logutil.DEBUG).Info("Prefix cache score for pod", "pod", endpoint.GetMetadata().String(), "prefixCacheScore", prefixCacheScore)
 
 		metricsStates[i] = endpoint.GetMetrics()
-		prompts[i] = request.Body.Completions.Prompt
+		prompts[i] = inputPrompt(request)
 		generatedTokenCounts[i] = 1
 		prefixCacheScores[i] = prefixCacheScore
 	}
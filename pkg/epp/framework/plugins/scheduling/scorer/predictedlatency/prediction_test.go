@@ -0,0 +1,51 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predictedlatency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	fwksched "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+func TestInputPrompt(t *testing.T) {
+	tests := []struct {
+		name        string
+		completions *fwksched.CompletionsRequest
+		want        string
+	}{
+		{
+			name:        "single prompt",
+			completions: &fwksched.CompletionsRequest{Prompt: "a single prompt"},
+			want:        "a single prompt",
+		},
+		{
+			name:        "batch uses its longest prompt",
+			completions: &fwksched.CompletionsRequest{Prompts: []string{"short", "the longest prompt", "medium prompt"}},
+			want:        "the longest prompt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &fwksched.LLMRequest{Body: &fwksched.LLMRequestBody{Completions: tt.completions}}
+			assert.Equal(t, tt.want, inputPrompt(request))
+		})
+	}
+}
@@ -486,6 +486,11 @@ func getUserInputBytes(request *framework.LLMRequest) ([]byte, error) {
 
 	case request.Body.Completions != nil:
 		// Handle completions API (maintain backward compatibility)
+		if prompts := request.Body.Completions.Prompts; len(prompts) > 0 {
+			// The model server caches each prompt of a batch on its own. The first prompt stands in for the batch,
+			// whose prompts commonly share a prefix.
+			return []byte(prompts[0]), nil
+		}
 		return []byte(request.Body.Completions.Prompt), nil
 
 	default:
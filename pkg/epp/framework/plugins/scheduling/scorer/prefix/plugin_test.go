@@ -239,6 +239,26 @@ func TestPrefixPluginCompletion(t *testing.T) {
 	plugin.wg.Wait()
 }
 
+func TestPrefixPluginCompletionsBatch(t *testing.T) {
+	newRequest := func(completions *fwksched.CompletionsRequest) *fwksched.LLMRequest {
+		return &fwksched.LLMRequest{
+			RequestId:   uuid.NewString(),
+			TargetModel: "test-model1",
+			Body:        &fwksched.LLMRequestBody{Completions: completions},
+		}
+	}
+	batch := newRequest(&fwksched.CompletionsRequest{Prompts: []string{"aaaaaaaa", "bbbbbbbb", "cccccccc"}})
+	first := newRequest(&fwksched.CompletionsRequest{Prompt: "aaaaaaaa"})
+	joined := newRequest(&fwksched.CompletionsRequest{Prompt: "aaaaaaaa\nbbbbbbbb\ncccccccc"})
+
+	ctx := context.Background()
+	batchHashes := hashPrompt(ctx, batch, 1, DefaultMaxPrefixBlocks)
+	assert.NotEmpty(t, batchHashes)
+	// A batch is hashed by its first prompt, the prefix a model server actually caches, not by the joined prompts.
+	assert.Equal(t, hashPrompt(ctx, first, 1, DefaultMaxPrefixBlocks), batchHashes)
+	assert.NotEqual(t, hashPrompt(ctx, joined, 1, DefaultMaxPrefixBlocks), batchHashes)
+}
+
 func TestPrefixPluginChatCompletions(t *testing.T) {
 	config := Config{
 		BlockSizeTokens:        1,
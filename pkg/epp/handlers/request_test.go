@@ -25,6 +25,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/types/known/structpb"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
 )
 
 func TestHandleRequestHeaders(t *testing.T) {
@@ -150,3 +152,116 @@ func TestGenerateRequestHeaderResponse_MergeMetadata(t *testing.T) {
 	assert.True(t, ok, "Expected DestinationEndpointKey to be in DestinationEndpointNamespace")
 	assert.Equal(t, "1.2.3.4:8080", endpointKey.GetStringValue(), "Unexpected value for DestinationEndpointKey")
 }
+
+func TestGenerateRequestHeaderResponse_RemoveHeaders(t *testing.T) {
+	t.Parallel()
+
+	server := &StreamingServer{}
+	reqCtx := &RequestContext{
+		TargetEndpoint:         "1.2.3.4:8080",
+		Request:                &Request{Headers: make(map[string]string)},
+		Response:               &Response{},
+		requestHeadersToRemove: []string{"content-encoding"},
+	}
+
+	resp := server.generateRequestHeaderResponse(context.Background(), reqCtx)
+
+	mutation := resp.GetRequestHeaders().GetResponse().GetHeaderMutation()
+	assert.Equal(t, []string{"content-encoding"}, mutation.GetRemoveHeaders())
+}
+
+func TestResolveRequestID(t *testing.T) {
+	t.Parallel()
+
+	const validID = "123e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name          string
+		format        string
+		validator     requtil.RequestIDValidator
+		reject        bool
+		requestID     string
+		wantID        string
+		wantGenerated bool
+		wantErr       bool
+	}{
+		{
+			name:      "any id is accepted without a validator",
+			requestID: "test-request-id",
+			wantID:    "test-request-id",
+		},
+		{
+			name:          "missing id is generated",
+			validator:     requtil.IsUUID,
+			wantGenerated: true,
+		},
+		{
+			name:      "valid id is accepted",
+			validator: requtil.IsUUID,
+			requestID: validID,
+			wantID:    validID,
+		},
+		{
+			name:          "invalid id is replaced",
+			validator:     requtil.IsUUID,
+			requestID:     "test-request-id",
+			wantGenerated: true,
+		},
+		{
+			name:      "invalid id is rejected",
+			validator: requtil.IsUUID,
+			reject:    true,
+			requestID: "test-request-id",
+			wantErr:   true,
+		},
+		{
+			name:          "invalid id is replaced with one in the prefix format",
+			format:        "prefix:tenant-a-",
+			requestID:     "tenant-b-1",
+			wantGenerated: true,
+		},
+		{
+			name:          "missing id is generated in the prefix format",
+			format:        "prefix:tenant-a-",
+			wantGenerated: true,
+		},
+		{
+			name:          "missing id is generated when rejecting invalid ids",
+			validator:     requtil.IsUUID,
+			reject:        true,
+			wantGenerated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			validator, generator := tt.validator, requtil.RequestIDGenerator(nil)
+			if tt.format != "" {
+				var err error
+				validator, generator, err = requtil.ParseRequestIDFormat(tt.format)
+				assert.NoError(t, err)
+			}
+			server := (&StreamingServer{}).WithRequestIDValidation(validator, generator, tt.reject)
+
+			id, generated, err := server.resolveRequestID(tt.requestID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, errutil.BadRequest, errutil.CanonicalCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantGenerated, generated)
+			if tt.wantGenerated {
+				if validator != nil {
+					assert.True(t, validator(id), "Expected a generated id satisfying the validator, got %q", id)
+				} else {
+					assert.True(t, requtil.IsUUID(id), "Expected a generated UUID, got %q", id)
+				}
+				assert.NotEqual(t, tt.requestID, id)
+			} else {
+				assert.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -34,8 +36,12 @@ import (
 )
 
 const (
-	streamingRespPrefix = "data: "
-	streamingEndMsg     = "data: [DONE]"
+	// streamingDataField prefixes the data lines of an SSE stream. The space that usually follows it is optional.
+	streamingDataField = "data:"
+	streamingEndData   = "[DONE]"
+
+	// usageHeaderKey is the response header in which some proxies report the usage object as JSON.
+	usageHeaderKey = "x-usage"
 
 	// OpenAI API object types
 	objectTypeResponse            = "response"
@@ -43,6 +49,7 @@ const (
 	objectTypeChatCompletion      = "chat.completion"
 	objectTypeChatCompletionChunk = "chat.completion.chunk"
 	objectTypeTextCompletion      = "text_completion"
+	objectTypeError               = "error"
 )
 
 // extractUsageByAPIType extracts usage statistics using the appropriate field names
@@ -53,43 +60,105 @@ func extractUsageByAPIType(usg map[string]any, objectType string) fwkrq.Usage {
 	switch {
 	case strings.HasPrefix(objectType, objectTypeResponse) || strings.HasPrefix(objectType, objectTypeConversation):
 		// Responses/Conversations APIs use input_tokens/output_tokens
-		if usg["input_tokens"] != nil {
-			usage.PromptTokens = int(usg["input_tokens"].(float64))
-		}
-		if usg["output_tokens"] != nil {
-			usage.CompletionTokens = int(usg["output_tokens"].(float64))
-		}
+		usage.PromptTokens, _ = tokenCount(usg, "input_tokens")
+		usage.CompletionTokens, _ = tokenCount(usg, "output_tokens")
 	case objectType == objectTypeChatCompletion || objectType == objectTypeChatCompletionChunk || objectType == objectTypeTextCompletion:
 		// Traditional APIs use prompt_tokens/completion_tokens
-		if usg["prompt_tokens"] != nil {
-			usage.PromptTokens = int(usg["prompt_tokens"].(float64))
-		}
-		if usg["completion_tokens"] != nil {
-			usage.CompletionTokens = int(usg["completion_tokens"].(float64))
-		}
+		usage.PromptTokens, _ = tokenCount(usg, "prompt_tokens")
+		usage.CompletionTokens, _ = tokenCount(usg, "completion_tokens")
 	default:
 		// Fallback: try both field naming conventions
-		if usg["input_tokens"] != nil {
-			usage.PromptTokens = int(usg["input_tokens"].(float64))
-		} else if usg["prompt_tokens"] != nil {
-			usage.PromptTokens = int(usg["prompt_tokens"].(float64))
+		var ok bool
+		if usage.PromptTokens, ok = tokenCount(usg, "input_tokens"); !ok {
+			usage.PromptTokens, _ = tokenCount(usg, "prompt_tokens")
 		}
-
-		if usg["output_tokens"] != nil {
-			usage.CompletionTokens = int(usg["output_tokens"].(float64))
-		} else if usg["completion_tokens"] != nil {
-			usage.CompletionTokens = int(usg["completion_tokens"].(float64))
+		if usage.CompletionTokens, ok = tokenCount(usg, "output_tokens"); !ok {
+			usage.CompletionTokens, _ = tokenCount(usg, "completion_tokens")
 		}
 	}
 
 	// total_tokens field name is consistent across all API types
-	if usg["total_tokens"] != nil {
-		usage.TotalTokens = int(usg["total_tokens"].(float64))
+	usage.TotalTokens, _ = tokenCount(usg, "total_tokens")
+
+	return usage
+}
+
+// extractUsage extracts usage statistics from a decoded response body.
+// It returns false if the body does not carry a usage object. Fields of an unexpected type are ignored, so
+// extraction never panics on a malformed response.
+func extractUsage(response map[string]any) (fwkrq.Usage, bool) {
+	usg, ok := response["usage"].(map[string]any)
+	if !ok {
+		return fwkrq.Usage{}, false
 	}
+	objectType, _ := response["object"].(string)
+	return extractUsageFields(usg, objectType), true
+}
 
+// extractUsageFields extracts usage statistics, including token details, from a usage object.
+func extractUsageFields(usg map[string]any, objectType string) fwkrq.Usage {
+	usage := extractUsageByAPIType(usg, objectType)
+	usage.PromptTokenDetails = extractPromptTokenDetails(usg)
+	usage.CompletionTokenDetails = extractCompletionTokenDetails(usg)
 	return usage
 }
 
+// extractPromptTokenDetails extracts the cached prompt token count, if reported.
+// OpenAI reports it under "prompt_tokens_details"; "prompt_token_details" is also
+// accepted for model servers that use that spelling.
+func extractPromptTokenDetails(usg map[string]any) *fwkrq.PromptTokenDetails {
+	for _, key := range []string{"prompt_tokens_details", "prompt_token_details"} {
+		details, ok := usageValue(usg, key).(map[string]any)
+		if !ok {
+			continue
+		}
+		if cachedTokens, ok := tokenCount(details, "cached_tokens"); ok {
+			return &fwkrq.PromptTokenDetails{CachedTokens: cachedTokens}
+		}
+	}
+	return nil
+}
+
+// extractCompletionTokenDetails extracts the reasoning token count, if reported.
+// Chat/Completions APIs report it under "completion_tokens_details", Responses APIs under "output_tokens_details".
+func extractCompletionTokenDetails(usg map[string]any) *fwkrq.CompletionTokenDetails {
+	for _, key := range []string{"completion_tokens_details", "output_tokens_details"} {
+		details, ok := usageValue(usg, key).(map[string]any)
+		if !ok {
+			continue
+		}
+		if reasoningTokens, ok := tokenCount(details, "reasoning_tokens"); ok {
+			return &fwkrq.CompletionTokenDetails{ReasoningTokens: reasoningTokens}
+		}
+	}
+	return nil
+}
+
+// tokenCount returns the value of a numeric usage field and whether it was present.
+func tokenCount(usg map[string]any, key string) (int, bool) {
+	count, ok := usageValue(usg, key).(float64)
+	return int(count), ok
+}
+
+// usageValue looks up a usage field by its snake_case key, falling back to the camelCase
+// spelling (e.g. "promptTokens") emitted by some OpenAI-compatible servers.
+func usageValue(usg map[string]any, key string) any {
+	if v := usg[key]; v != nil {
+		return v
+	}
+	return usg[snakeToCamel(key)]
+}
+
+func snakeToCamel(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
 // HandleResponseBody always returns the requestContext even in the error case, as the request context is used in error handling.
 func (s *StreamingServer) HandleResponseBody(ctx context.Context, reqCtx *RequestContext, response map[string]any) (*RequestContext, error) {
 	logger := log.FromContext(ctx)
@@ -97,18 +166,14 @@ func (s *StreamingServer) HandleResponseBody(ctx context.Context, reqCtx *Reques
 	if err != nil {
 		return reqCtx, fmt.Errorf("error marshalling responseBody - %w", err)
 	}
-	if response["usage"] != nil {
-		usg := response["usage"].(map[string]any)
-		objectType, _ := response["object"].(string)
-		usage := extractUsageByAPIType(usg, objectType)
-		if usg["prompt_token_details"] != nil {
-			detailsMap := usg["prompt_token_details"].(map[string]any)
-			if cachedTokens, ok := detailsMap["cached_tokens"]; ok {
-				usage.PromptTokenDetails = &fwkrq.PromptTokenDetails{
-					CachedTokens: int(cachedTokens.(float64)),
-				}
-			}
-		}
+	reqCtx.ResponseObjectType, _ = response["object"].(string)
+	reqCtx.ResponseFinishReason = extractFinishReason(response)
+	reqCtx.ResponseModel, _ = response["model"].(string)
+	reqCtx.ResponseToolCallsDetected = hasToolCalls(response)
+	reqCtx.ResponseReasoningPresent = hasReasoningContent(response)
+	// The error body is still relayed to the client as is, the error only tells plugins what the model server reported.
+	reqCtx.ModelServerError = extractModelServerError(response)
+	if usage, ok := extractUsage(response); ok {
 		reqCtx.Usage = usage
 		logger.V(logutil.VERBOSE).Info("Response generated", "usage", reqCtx.Usage)
 	}
@@ -125,20 +190,113 @@ func (s *StreamingServer) HandleResponseBody(ctx context.Context, reqCtx *Reques
 	return s.director.HandleResponseBodyComplete(ctx, reqCtx)
 }
 
+// extractFinishReason returns the first finish reason reported by the choices of a decoded response body, normalized
+// to the OpenAI canonical set, or an empty string if none reports one yet.
+func extractFinishReason(response map[string]any) string {
+	choices, _ := response["choices"].([]any)
+	for _, choice := range choices {
+		fields, _ := choice.(map[string]any)
+		if finishReason, _ := fields["finish_reason"].(string); finishReason != "" {
+			return fwkrq.NormalizeFinishReason(finishReason)
+		}
+	}
+	return ""
+}
+
+// extractModelServerError detects an error object in a decoded response body, returning nil if there is none.
+// OpenAI nests the fields under an "error" key, while vLLM returns them at the top level of an object of type "error".
+func extractModelServerError(response map[string]any) *fwkrq.ModelServerError {
+	fields, ok := response["error"].(map[string]any)
+	if !ok {
+		if objectType, _ := response["object"].(string); objectType != objectTypeError {
+			return nil
+		}
+		fields = response
+	}
+
+	modelServerErr := &fwkrq.ModelServerError{}
+	modelServerErr.Message, _ = fields["message"].(string)
+	modelServerErr.Type, _ = fields["type"].(string)
+	switch code := fields["code"].(type) {
+	case string:
+		modelServerErr.Code = code
+	case float64:
+		modelServerErr.Code = strconv.FormatFloat(code, 'f', -1, 64)
+	}
+	return modelServerErr
+}
+
+// choiceMessages returns the message of each choice of a decoded response body, or its delta for a streamed chunk.
+func choiceMessages(response map[string]any) []map[string]any {
+	choices, _ := response["choices"].([]any)
+	messages := make([]map[string]any, 0, len(choices))
+	for _, choice := range choices {
+		fields, _ := choice.(map[string]any)
+		for _, key := range []string{"delta", "message"} {
+			if message, ok := fields[key].(map[string]any); ok {
+				messages = append(messages, message)
+			}
+		}
+	}
+	return messages
+}
+
+// hasToolCalls reports whether a decoded response body, or streamed chunk, shows the model invoking tools: a choice
+// carries tool calls, or finished with the "tool_calls" reason.
+func hasToolCalls(response map[string]any) bool {
+	for _, message := range choiceMessages(response) {
+		if toolCalls, _ := message["tool_calls"].([]any); len(toolCalls) > 0 {
+			return true
+		}
+	}
+	return extractFinishReason(response) == fwkrq.FinishReasonToolCalls
+}
+
+// hasReasoningContent reports whether a choice of a decoded response body, or streamed chunk, carries non-empty
+// reasoning content, as emitted separately from the answer by reasoning models such as DeepSeek-R1 or Qwen3.
+func hasReasoningContent(response map[string]any) bool {
+	for _, message := range choiceMessages(response) {
+		if reasoning, _ := message["reasoning_content"].(string); reasoning != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // The function is to handle streaming response if the modelServer is streaming.
 func (s *StreamingServer) HandleResponseBodyModelStreaming(ctx context.Context, reqCtx *RequestContext, responseText string) {
 	logger := log.FromContext(ctx)
+	resp := parseRespForUsage(ctx, responseText)
+	if reqCtx.ResponseObjectType == "" && resp.ObjectType != "" {
+		reqCtx.ResponseObjectType = resp.ObjectType
+		if !isStreamingObjectType(resp.ObjectType) {
+			logger.V(logutil.DEFAULT).Info("Unexpected object type in streamed response", "objectType", resp.ObjectType)
+		}
+	}
+	if resp.FinishReason != "" {
+		reqCtx.ResponseFinishReason = resp.FinishReason
+	}
+	if reqCtx.ResponseModel == "" {
+		reqCtx.ResponseModel = resp.Model
+	}
+	if resp.ToolCallsDetected {
+		reqCtx.ResponseToolCallsDetected = true
+	}
+	if resp.ReasoningContentPresent {
+		reqCtx.ResponseReasoningPresent = true
+	}
+
 	_, err := s.director.HandleResponseBodyStreaming(ctx, reqCtx)
 	if err != nil {
 		logger.Error(err, "error in HandleResponseBodyStreaming")
 	}
 
 	// Parse usage on EVERY chunk to catch split streams (where usage and [DONE] are in different chunks).
-	if resp := parseRespForUsage(ctx, responseText); resp.Usage.TotalTokens > 0 {
+	if resp.Usage.TotalTokens > 0 {
 		reqCtx.Usage = resp.Usage
 	}
 
-	if strings.Contains(responseText, streamingEndMsg) {
+	if isStreamEnd(responseText) {
 		reqCtx.ResponseComplete = true
 		metrics.RecordInputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.PromptTokens)
 		metrics.RecordOutputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.CompletionTokens)
@@ -155,6 +313,16 @@ func (s *StreamingServer) HandleResponseHeaders(ctx context.Context, reqCtx *Req
 		reqCtx.Response.Headers[header.Key] = request.GetHeaderValue(header)
 	}
 
+	// Some proxies report usage in a header rather than the body. Usage found in the body takes precedence.
+	if usageHeader := reqCtx.Response.Headers[usageHeaderKey]; usageHeader != "" {
+		var usg map[string]any
+		if err := json.Unmarshal([]byte(usageHeader), &usg); err != nil {
+			log.FromContext(ctx).V(logutil.DEFAULT).Error(err, "Ignoring malformed usage header", "header", usageHeaderKey)
+		} else {
+			reqCtx.Usage = extractUsageFields(usg, "")
+		}
+	}
+
 	reqCtx, err := s.director.HandleResponseReceived(ctx, reqCtx)
 
 	return reqCtx, err
@@ -217,6 +385,61 @@ func (s *StreamingServer) generateResponseHeaders(reqCtx *RequestContext) []*con
 	return headers
 }
 
+// reconcileResponseFraming checks the first non-empty response body chunk against the streaming mode inferred from
+// the response content-type, and corrects the mode when a misconfigured model server frames the body differently:
+// SSE frames sent without a text/event-stream content-type are handled as a stream, and a JSON object sent with a
+// text/event-stream content-type is buffered and handled as a unary response.
+func (r *RequestContext) reconcileResponseFraming(ctx context.Context, chunk []byte) {
+	trimmed := bytes.TrimLeft(chunk, " \t\r\n")
+	if len(trimmed) == 0 {
+		return
+	}
+	r.responseFramingChecked = true
+
+	logger := log.FromContext(ctx)
+	if !r.modelServerStreaming && isSSEFrame(trimmed) {
+		logger.V(logutil.DEFAULT).Info("Model server sent a streamed response without a text/event-stream content-type, handling it as a stream")
+		r.modelServerStreaming = true
+	} else if r.modelServerStreaming && trimmed[0] == '{' {
+		logger.V(logutil.DEFAULT).Info("Model server sent a unary response with a text/event-stream content-type, handling it as unary")
+		r.modelServerStreaming = false
+	}
+}
+
+// sseLinePrefixes are the prefixes an SSE stream can open with: the data, event, id and retry fields, and a ":"
+// comment such as a keep-alive.
+var sseLinePrefixes = [][]byte{[]byte(streamingDataField), []byte("event:"), []byte("id:"), []byte("retry:"), []byte(":")}
+
+// isSSEFrame reports whether a response body chunk, with leading whitespace trimmed, opens with an SSE line.
+func isSSEFrame(trimmed []byte) bool {
+	for _, prefix := range sseLinePrefixes {
+		if bytes.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sseData returns the value of an SSE data line, with the optional space after the field name removed, and whether
+// the line is a data line.
+func sseData(line string) (string, bool) {
+	data, ok := strings.CutPrefix(strings.TrimSuffix(line, "\r"), streamingDataField)
+	if !ok {
+		return "", false
+	}
+	return strings.TrimPrefix(data, " "), true
+}
+
+// isStreamEnd reports whether a streamed response chunk carries the data line that ends the stream.
+func isStreamEnd(responseText string) bool {
+	for line := range strings.SplitSeq(responseText, "\n") {
+		if data, ok := sseData(line); ok && data == streamingEndData {
+			return true
+		}
+	}
+	return false
+}
+
 // Example message if "stream_options": {"include_usage": "true"} is included in the request:
 // data: {"id":"...","object":"text_completion","created":1739400043,"model":"food-review-0","choices":[],
 // "usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}
@@ -234,26 +457,58 @@ func parseRespForUsage(ctx context.Context, responseText string) ResponseBody {
 
 	lines := strings.SplitSeq(responseText, "\n")
 	for line := range lines {
-		if !strings.HasPrefix(line, streamingRespPrefix) {
-			continue
-		}
-		content := strings.TrimPrefix(line, streamingRespPrefix)
-		if content == "[DONE]" {
+		content, ok := sseData(line)
+		if !ok || content == streamingEndData {
 			continue
 		}
 
-		byteSlice := []byte(content)
-		if err := json.Unmarshal(byteSlice, &response); err != nil {
+		var chunk map[string]any
+		if err := json.Unmarshal([]byte(content), &chunk); err != nil {
 			logger.Error(err, "unmarshaling response body")
 			continue
 		}
+		if usage, ok := extractUsage(chunk); ok {
+			response.Usage = usage
+		}
+		if response.ObjectType == "" {
+			response.ObjectType, _ = chunk["object"].(string)
+		}
+		if response.Model == "" {
+			response.Model, _ = chunk["model"].(string)
+		}
+		if finishReason := extractFinishReason(chunk); finishReason != "" {
+			response.FinishReason = finishReason
+		}
+		if hasToolCalls(chunk) {
+			response.ToolCallsDetected = true
+		}
+		if hasReasoningContent(chunk) {
+			response.ReasoningContentPresent = true
+		}
 	}
 
 	return response
 }
 
+// isStreamingObjectType reports whether an object type is expected in a streamed response. A streamed chat
+// completion that reports "chat.completion" or "error" instead of "chat.completion.chunk" was not streamed as such.
+func isStreamingObjectType(objectType string) bool {
+	return objectType == objectTypeChatCompletionChunk || objectType == objectTypeTextCompletion ||
+		strings.HasPrefix(objectType, objectTypeResponse)
+}
+
 type ResponseBody struct {
 	Usage fwkrq.Usage `json:"usage"`
+	// ObjectType is the object type of the first chunk that declares one.
+	ObjectType string `json:"object,omitempty"`
+	// FinishReason is the last finish reason reported by a chunk.
+	FinishReason string `json:"finish_reason,omitempty"`
+	// Model is the model reported by the first chunk that declares one.
+	Model string `json:"model,omitempty"`
+	// ToolCallsDetected is set once a chunk carries tool call deltas or a "tool_calls" finish reason.
+	ToolCallsDetected bool `json:"-"`
+	// ReasoningContentPresent is set once a chunk carries a non-empty "reasoning_content" delta.
+	ReasoningContentPresent bool `json:"-"`
 }
 
 type PromptTokenDetails struct {
@@ -21,6 +21,8 @@ import (
 	"encoding/json"
 	"testing"
 
+	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 
@@ -80,6 +82,74 @@ const (
 		}
 	}
 	`
+	bodyWithPromptTokensDetails = `
+	{
+		"id": "chatcmpl-9f3b0c1e2d4a4b7c8e6f5a4b3c2d1e0f",
+		"object": "chat.completion",
+		"created": 1732563765,
+		"model": "meta-llama/Llama-3.1-8B-Instruct",
+		"choices": [
+			{
+				"index": 0,
+				"message": {"role": "assistant", "content": "Hello!"},
+				"finish_reason": "stop"
+			}
+		],
+		"usage": {
+			"prompt_tokens": 2006,
+			"total_tokens": 2306,
+			"completion_tokens": 300,
+			"prompt_tokens_details": {
+				"cached_tokens": 1920
+			}
+		}
+	}
+	`
+	bodyWithReasoningTokens = `
+	{
+		"id": "chatcmpl-AEAtJ7ITrfNqnVTINZRYVSlnlBoTe",
+		"object": "chat.completion",
+		"created": 1727913439,
+		"model": "o1-preview-2024-09-12",
+		"choices": [
+			{
+				"index": 0,
+				"message": {"role": "assistant", "content": "Here is the answer.", "refusal": null},
+				"finish_reason": "stop"
+			}
+		],
+		"usage": {
+			"prompt_tokens": 31,
+			"completion_tokens": 2893,
+			"total_tokens": 2924,
+			"prompt_tokens_details": {
+				"cached_tokens": 0
+			},
+			"completion_tokens_details": {
+				"reasoning_tokens": 2368,
+				"accepted_prediction_tokens": 0,
+				"rejected_prediction_tokens": 0
+			}
+		}
+	}
+	`
+	bodyWithCamelCaseUsage = `
+	{
+		"id": "chatcmpl-3c2d1e0f9f3b0c1e2d4a4b7c8e6f5a4b",
+		"object": "chat.completion",
+		"created": 1732563765,
+		"model": "meta-llama/Llama-3.1-8B-Instruct",
+		"choices": [],
+		"usage": {
+			"promptTokens": 12,
+			"completionTokens": 30,
+			"totalTokens": 42,
+			"promptTokensDetails": {
+				"cachedTokens": 8
+			}
+		}
+	}
+	`
 
 	streamingBodyWithoutUsage = `data: {"id":"cmpl-41764c93-f9d2-4f31-be08-3ba04fa25394","object":"text_completion","created":1740002445,"model":"food-review-0","choices":[],"usage":null}
 	`
@@ -88,6 +158,12 @@ const (
 data: [DONE]
 	`
 	streamingBodyWithUsageAndCachedTokens = `data: {"id":"cmpl-41764c93-f9d2-4f31-be08-3ba04fa25394","object":"text_completion","created":1740002445,"model":"food-review-0","choices":[],"usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10,"prompt_token_details":{"cached_tokens":5}}}
+data: [DONE]
+	`
+	streamingBodyWithUsageAndPromptTokensDetails = `data: {"id":"chatcmpl-9f3b0c1e","object":"chat.completion.chunk","created":1740002445,"model":"food-review-0","choices":[],"usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10,"prompt_tokens_details":{"cached_tokens":6}}}
+data: [DONE]
+	`
+	streamingBodyWithCamelCaseUsage = `data: {"id":"chatcmpl-9f3b0c1e","object":"chat.completion.chunk","created":1740002445,"model":"food-review-0","choices":[],"usage":{"promptTokens":7,"totalTokens":17,"completionTokens":10}}
 data: [DONE]
 	`
 )
@@ -144,6 +220,45 @@ func TestHandleResponseBody(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "success with prompt_tokens_details",
+			body: []byte(bodyWithPromptTokensDetails),
+			want: fwkrq.Usage{
+				PromptTokens:     2006,
+				TotalTokens:      2306,
+				CompletionTokens: 300,
+				PromptTokenDetails: &fwkrq.PromptTokenDetails{
+					CachedTokens: 1920,
+				},
+			},
+		},
+		{
+			name: "success with reasoning tokens",
+			body: []byte(bodyWithReasoningTokens),
+			want: fwkrq.Usage{
+				PromptTokens:     31,
+				TotalTokens:      2924,
+				CompletionTokens: 2893,
+				PromptTokenDetails: &fwkrq.PromptTokenDetails{
+					CachedTokens: 0,
+				},
+				CompletionTokenDetails: &fwkrq.CompletionTokenDetails{
+					ReasoningTokens: 2368,
+				},
+			},
+		},
+		{
+			name: "success with camelCase usage",
+			body: []byte(bodyWithCamelCaseUsage),
+			want: fwkrq.Usage{
+				PromptTokens:     12,
+				TotalTokens:      42,
+				CompletionTokens: 30,
+				PromptTokenDetails: &fwkrq.PromptTokenDetails{
+					CachedTokens: 8,
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -221,6 +336,35 @@ func TestHandleStreamedResponseBody(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "streaming request with usage and prompt_tokens_details",
+			body: streamingBodyWithUsageAndPromptTokensDetails,
+			reqCtx: &RequestContext{
+				modelServerStreaming: true,
+			},
+			wantErr: false,
+			want: fwkrq.Usage{
+				PromptTokens:     7,
+				TotalTokens:      17,
+				CompletionTokens: 10,
+				PromptTokenDetails: &fwkrq.PromptTokenDetails{
+					CachedTokens: 6,
+				},
+			},
+		},
+		{
+			name: "streaming request with camelCase usage",
+			body: streamingBodyWithCamelCaseUsage,
+			reqCtx: &RequestContext{
+				modelServerStreaming: true,
+			},
+			wantErr: false,
+			want: fwkrq.Usage{
+				PromptTokens:     7,
+				TotalTokens:      17,
+				CompletionTokens: 10,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -274,6 +418,15 @@ func TestHandleResponseBodyModelStreaming_TokenAccumulation(t *testing.T) {
 			},
 			wantUsage: fwkrq.Usage{PromptTokens: 5, CompletionTokens: 10, TotalTokens: 15},
 		},
+		{
+			name: "Obfuscation padding alongside content and usage",
+			chunks: []string{
+				`data: {"object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"Hello"}}],"obfuscation":"q8Zx3"}` + "\n",
+				`data: {"object":"chat.completion.chunk","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":10,"total_tokens":15},"obfuscation":"Jk2mP0aRt"}` + "\n",
+				`data: [DONE]`,
+			},
+			wantUsage: fwkrq.Usage{PromptTokens: 5, CompletionTokens: 10, TotalTokens: 15},
+		},
 		{
 			name: "No Usage Data",
 			chunks: []string{
@@ -327,3 +480,630 @@ func TestGenerateResponseHeaders_Sanitization(t *testing.T) {
 	assert.NotContains(t, gotHeaders, metadata.DestinationEndpointKey)
 	assert.NotContains(t, gotHeaders, "content-length")
 }
+
+func TestResponseObjectType(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	tests := []struct {
+		name      string
+		body      string
+		streaming bool
+		want      string
+	}{
+		{
+			name: "chat completion",
+			body: `{"object":"chat.completion","choices":[],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`,
+			want: objectTypeChatCompletion,
+		},
+		{
+			name: "text completion",
+			body: `{"object":"text_completion","choices":[{"text":"hi"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`,
+			want: objectTypeTextCompletion,
+		},
+		{
+			name: "responses object",
+			body: `{"object":"response","output":[],"usage":{"input_tokens":1,"output_tokens":1,"total_tokens":2}}`,
+			want: objectTypeResponse,
+		},
+		{
+			name: "error object",
+			body: `{"object":"error","message":"model not found","type":"NotFoundError","code":404}`,
+			want: "error",
+		},
+		{
+			name: "body without object type",
+			body: `{"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`,
+			want: "",
+		},
+		{
+			name:      "streamed text completion",
+			body:      streamingBodyWithUsage,
+			streaming: true,
+			want:      objectTypeTextCompletion,
+		},
+		{
+			name:      "first declared type in a stream",
+			body:      "data: {\"choices\":[]}\n\ndata: {\"object\":\"chat.completion.chunk\",\"choices\":[]}\n\ndata: {\"object\":\"other\"}\n",
+			streaming: true,
+			want:      objectTypeChatCompletionChunk,
+		},
+		{
+			name:      "unexpected type in a stream",
+			body:      "data: {\"object\":\"chat.completion\",\"choices\":[]}\n",
+			streaming: true,
+			want:      objectTypeChatCompletion,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{director: &mockDirector{}}
+			reqCtx := &RequestContext{modelServerStreaming: test.streaming}
+			if test.streaming {
+				server.HandleResponseBodyModelStreaming(ctx, reqCtx, test.body)
+			} else {
+				var responseMap map[string]any
+				if err := json.Unmarshal([]byte(test.body), &responseMap); err != nil {
+					t.Fatalf("Error unmarshaling response body: %v", err)
+				}
+				if _, err := server.HandleResponseBody(ctx, reqCtx, responseMap); err != nil {
+					t.Fatalf("HandleResponseBody returned unexpected error: %v", err)
+				}
+			}
+			assert.Equal(t, test.want, reqCtx.ResponseObjectType)
+		})
+	}
+}
+
+func TestResponseFinishReason(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	tests := []struct {
+		name         string
+		body         string
+		streaming    bool
+		want         string
+		wantToolCall bool
+	}{
+		{
+			name:         "tool calls",
+			body:         `{"object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{}"}}]},"finish_reason":"tool_calls"}]}`,
+			want:         "tool_calls",
+			wantToolCall: true,
+		},
+		{
+			name: "stop",
+			body: `{"object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`,
+			want: "stop",
+		},
+		{
+			name: "length",
+			body: body,
+			want: "length",
+		},
+		{
+			name: "backend-specific finish reason is normalized",
+			body: `{"object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"eos_token"}]}`,
+			want: "stop",
+		},
+		{
+			name: "body without choices",
+			body: `{"object":"response","output":[]}`,
+			want: "",
+		},
+		{
+			name:         "streamed tool calls",
+			body:         "data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"id\":\"call_1\"}]},\"finish_reason\":null}]}\n\ndata: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"tool_calls\"}]}\n\ndata: [DONE]\n",
+			streaming:    true,
+			want:         "tool_calls",
+			wantToolCall: true,
+		},
+		{
+			name:         "streamed backend-specific tool use is normalized",
+			body:         "data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"tool_use\"}]}\n\ndata: [DONE]\n",
+			streaming:    true,
+			want:         "tool_calls",
+			wantToolCall: true,
+		},
+		{
+			name:      "stream without a finish reason yet",
+			body:      "data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"},\"finish_reason\":null}]}\n",
+			streaming: true,
+			want:      "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{director: &mockDirector{}}
+			reqCtx := &RequestContext{modelServerStreaming: test.streaming}
+			if test.streaming {
+				server.HandleResponseBodyModelStreaming(ctx, reqCtx, test.body)
+			} else {
+				var responseMap map[string]any
+				if err := json.Unmarshal([]byte(test.body), &responseMap); err != nil {
+					t.Fatalf("Error unmarshaling response body: %v", err)
+				}
+				if _, err := server.HandleResponseBody(ctx, reqCtx, responseMap); err != nil {
+					t.Fatalf("HandleResponseBody returned unexpected error: %v", err)
+				}
+			}
+			assert.Equal(t, test.want, reqCtx.ResponseFinishReason)
+			response := &fwkrq.Response{FinishReason: reqCtx.ResponseFinishReason}
+			assert.Equal(t, test.wantToolCall, response.IsToolCall())
+		})
+	}
+}
+
+func TestResponseModel(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	tests := []struct {
+		name      string
+		chunks    []string
+		streaming bool
+		want      string
+	}{
+		{
+			name:   "unary response",
+			chunks: []string{body},
+			want:   "meta-llama/Llama-3.1-8B-Instruct",
+		},
+		{
+			name:      "model from the first chunk",
+			chunks:    []string{streamingBodyWithUsage},
+			streaming: true,
+			want:      "food-review-0",
+		},
+		{
+			name: "first non-empty model across chunks",
+			chunks: []string{
+				"data: {\"object\":\"chat.completion.chunk\",\"model\":\"\",\"choices\":[]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"model\":\"served-model\",\"choices\":[]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"model\":\"other-model\",\"choices\":[]}\n\ndata: [DONE]\n",
+			},
+			streaming: true,
+			want:      "served-model",
+		},
+		{
+			name:      "stream without a model",
+			chunks:    []string{"data: {\"object\":\"chat.completion.chunk\",\"choices\":[]}\n"},
+			streaming: true,
+			want:      "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{director: &mockDirector{}}
+			reqCtx := &RequestContext{modelServerStreaming: test.streaming}
+			for _, chunk := range test.chunks {
+				if test.streaming {
+					server.HandleResponseBodyModelStreaming(ctx, reqCtx, chunk)
+					continue
+				}
+				var responseMap map[string]any
+				if err := json.Unmarshal([]byte(chunk), &responseMap); err != nil {
+					t.Fatalf("Error unmarshaling response body: %v", err)
+				}
+				if _, err := server.HandleResponseBody(ctx, reqCtx, responseMap); err != nil {
+					t.Fatalf("HandleResponseBody returned unexpected error: %v", err)
+				}
+			}
+			assert.Equal(t, test.want, reqCtx.ResponseModel)
+		})
+	}
+}
+
+func TestResponseToolCallsDetected(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	tests := []struct {
+		name      string
+		chunks    []string
+		streaming bool
+		want      bool
+	}{
+		{
+			name:   "unary response without tool calls",
+			chunks: []string{body},
+			want:   false,
+		},
+		{
+			name:   "unary response with tool calls",
+			chunks: []string{`{"object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{}"}}]},"finish_reason":"tool_calls"}]}`},
+			want:   true,
+		},
+		{
+			name: "streamed tool call deltas",
+			chunks: []string{
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"tool_calls\":[{\"index\":0,\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"get_weather\",\"arguments\":\"\"}}]},\"finish_reason\":null}]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"arguments\":\"{\\\"city\\\":\\\"Paris\\\"}\"}}]},\"finish_reason\":null}]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":null}]}\n\ndata: [DONE]\n",
+			},
+			streaming: true,
+			want:      true,
+		},
+		{
+			name: "tool_calls finish reason without deltas",
+			chunks: []string{
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\"},\"finish_reason\":null}]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"tool_calls\"}]}\n\ndata: [DONE]\n",
+			},
+			streaming: true,
+			want:      true,
+		},
+		{
+			name:      "streamed content only",
+			chunks:    []string{streamingBodyWithUsage},
+			streaming: true,
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{director: &mockDirector{}}
+			reqCtx := &RequestContext{modelServerStreaming: test.streaming}
+			for _, chunk := range test.chunks {
+				if test.streaming {
+					server.HandleResponseBodyModelStreaming(ctx, reqCtx, chunk)
+					continue
+				}
+				var responseMap map[string]any
+				if err := json.Unmarshal([]byte(chunk), &responseMap); err != nil {
+					t.Fatalf("Error unmarshaling response body: %v", err)
+				}
+				if _, err := server.HandleResponseBody(ctx, reqCtx, responseMap); err != nil {
+					t.Fatalf("HandleResponseBody returned unexpected error: %v", err)
+				}
+			}
+			assert.Equal(t, test.want, reqCtx.ResponseToolCallsDetected)
+		})
+	}
+}
+
+func TestResponseReasoningPresent(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	tests := []struct {
+		name      string
+		chunks    []string
+		streaming bool
+		want      bool
+		wantUsage fwkrq.Usage
+	}{
+		{
+			name:   "unary response without reasoning",
+			chunks: []string{body},
+			want:   false,
+			wantUsage: fwkrq.Usage{
+				PromptTokens:     11,
+				CompletionTokens: 100,
+				TotalTokens:      111,
+			},
+		},
+		{
+			name:   "unary response with reasoning",
+			chunks: []string{`{"object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","reasoning_content":"Two plus two is four.","content":"4"},"finish_reason":"stop"}],"usage":{"prompt_tokens":9,"completion_tokens":12,"total_tokens":21}}`},
+			want:   true,
+			wantUsage: fwkrq.Usage{
+				PromptTokens:     9,
+				CompletionTokens: 12,
+				TotalTokens:      21,
+			},
+		},
+		{
+			name: "interleaved reasoning and content deltas",
+			chunks: []string{
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"\"},\"finish_reason\":null}]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"reasoning_content\":\"Two plus two\"},\"finish_reason\":null}]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"The answer\"},\"finish_reason\":null}]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"reasoning_content\":\" is four.\"},\"finish_reason\":null}]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\" is 4.\"},\"finish_reason\":\"stop\"}]}\n",
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[],\"usage\":{\"prompt_tokens\":9,\"completion_tokens\":12,\"total_tokens\":21}}\n\ndata: [DONE]\n",
+			},
+			streaming: true,
+			want:      true,
+			wantUsage: fwkrq.Usage{
+				PromptTokens:     9,
+				CompletionTokens: 12,
+				TotalTokens:      21,
+			},
+		},
+		{
+			name: "empty reasoning deltas",
+			chunks: []string{
+				"data: {\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"reasoning_content\":\"\",\"content\":\"4\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n",
+			},
+			streaming: true,
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{director: &mockDirector{}}
+			reqCtx := &RequestContext{modelServerStreaming: test.streaming}
+			for _, chunk := range test.chunks {
+				if test.streaming {
+					server.HandleResponseBodyModelStreaming(ctx, reqCtx, chunk)
+					continue
+				}
+				var responseMap map[string]any
+				if err := json.Unmarshal([]byte(chunk), &responseMap); err != nil {
+					t.Fatalf("Error unmarshaling response body: %v", err)
+				}
+				if _, err := server.HandleResponseBody(ctx, reqCtx, responseMap); err != nil {
+					t.Fatalf("HandleResponseBody returned unexpected error: %v", err)
+				}
+			}
+			assert.Equal(t, test.want, reqCtx.ResponseReasoningPresent)
+			// Content handling is unaffected by reasoning deltas.
+			assert.Equal(t, test.wantUsage, reqCtx.Usage)
+		})
+	}
+}
+
+func TestHandleResponseBody_ModelServerError(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	tests := []struct {
+		name string
+		body string
+		want *fwkrq.ModelServerError
+	}{
+		{
+			name: "rate limit error",
+			body: `{"error":{"message":"Rate limit reached for requests","type":"requests","param":null,"code":"rate_limit_exceeded"}}`,
+			want: &fwkrq.ModelServerError{Message: "Rate limit reached for requests", Type: "requests", Code: "rate_limit_exceeded"},
+		},
+		{
+			name: "invalid request error",
+			body: `{"error":{"message":"'messages' is a required property","type":"invalid_request_error","param":"messages","code":null}}`,
+			want: &fwkrq.ModelServerError{Message: "'messages' is a required property", Type: "invalid_request_error"},
+		},
+		{
+			name: "vLLM error object",
+			body: `{"object":"error","message":"The model does not exist.","type":"NotFoundError","param":null,"code":404}`,
+			want: &fwkrq.ModelServerError{Message: "The model does not exist.", Type: "NotFoundError", Code: "404"},
+		},
+		{
+			name: "successful response",
+			body: `{"object":"chat.completion","choices":[],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{director: &mockDirector{}}
+			reqCtx := &RequestContext{}
+			var responseMap map[string]any
+			if err := json.Unmarshal([]byte(test.body), &responseMap); err != nil {
+				t.Fatalf("Error unmarshaling response body: %v", err)
+			}
+
+			// A relayed error object is not a processing failure.
+			_, err := server.HandleResponseBody(ctx, reqCtx, responseMap)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, reqCtx.ModelServerError)
+			// The body must be relayed to the client either way.
+			assert.NotEmpty(t, reqCtx.respBodyResp)
+			assert.True(t, reqCtx.ResponseComplete)
+		})
+	}
+}
+
+func TestReconcileResponseFraming(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	tests := []struct {
+		name          string
+		streaming     bool
+		chunks        []string
+		wantStreaming bool
+		wantUsage     fwkrq.Usage
+	}{
+		{
+			name:          "streamed body declared as unary is handled as a stream",
+			streaming:     false,
+			chunks:        []string{streamingBodyWithUsage},
+			wantStreaming: true,
+			wantUsage:     fwkrq.Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name:          "unary body declared as a stream is handled as unary",
+			streaming:     true,
+			chunks:        []string{body},
+			wantStreaming: false,
+			wantUsage:     fwkrq.Usage{PromptTokens: 11, TotalTokens: 111, CompletionTokens: 100},
+		},
+		{
+			name:          "matching framing is left unchanged",
+			streaming:     true,
+			chunks:        []string{streamingBodyWithUsage},
+			wantStreaming: true,
+			wantUsage:     fwkrq.Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name:          "stream opening with an event field is handled as a stream",
+			streaming:     false,
+			chunks:        []string{"event: message\n" + streamingBodyWithUsage},
+			wantStreaming: true,
+			wantUsage:     fwkrq.Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name:          "stream opening with an id field is handled as a stream",
+			streaming:     false,
+			chunks:        []string{"id: 1\n" + streamingBodyWithUsage},
+			wantStreaming: true,
+			wantUsage:     fwkrq.Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name:          "stream opening with a retry field is handled as a stream",
+			streaming:     false,
+			chunks:        []string{"retry: 3000\n\n", streamingBodyWithUsage},
+			wantStreaming: true,
+			wantUsage:     fwkrq.Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name:          "stream opening with a keep-alive comment is handled as a stream",
+			streaming:     false,
+			chunks:        []string{": keep-alive\n\n", streamingBodyWithUsage},
+			wantStreaming: true,
+			wantUsage:     fwkrq.Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name:      "data lines without a space after the field name are parsed",
+			streaming: false,
+			chunks: []string{
+				"data:{\"object\":\"text_completion\",\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"total_tokens\":17,\"completion_tokens\":10}}\n\ndata:[DONE]\n",
+			},
+			wantStreaming: true,
+			wantUsage:     fwkrq.Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name:          "empty leading chunk defers the check",
+			streaming:     false,
+			chunks:        []string{"", streamingBodyWithUsage},
+			wantStreaming: true,
+			wantUsage:     fwkrq.Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{director: &mockDirector{}}
+			reqCtx := &RequestContext{modelServerStreaming: test.streaming}
+
+			var buffered []byte
+			for i, chunk := range test.chunks {
+				respBody := &extProcPb.HttpBody{Body: []byte(chunk), EndOfStream: i == len(test.chunks)-1}
+				buffered = server.processResponseBody(ctx, reqCtx, respBody, buffered)
+			}
+
+			assert.Equal(t, test.wantStreaming, reqCtx.modelServerStreaming)
+			assert.Equal(t, test.wantUsage, reqCtx.Usage)
+			assert.True(t, reqCtx.ResponseComplete)
+			assert.NotEmpty(t, reqCtx.respBodyResp)
+		})
+	}
+}
+
+func TestHandleResponseHeaders_UsageHeader(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+
+	tests := []struct {
+		name        string
+		usageHeader string
+		body        string
+		want        fwkrq.Usage
+	}{
+		{
+			name:        "well-formed usage header",
+			usageHeader: `{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7,"prompt_tokens_details":{"cached_tokens":2}}`,
+			want: fwkrq.Usage{
+				PromptTokens:       3,
+				CompletionTokens:   4,
+				TotalTokens:        7,
+				PromptTokenDetails: &fwkrq.PromptTokenDetails{CachedTokens: 2},
+			},
+		},
+		{
+			name:        "malformed usage header is ignored",
+			usageHeader: `{"prompt_tokens":3,`,
+			want:        fwkrq.Usage{},
+		},
+		{
+			name:        "usage in body takes precedence over header",
+			usageHeader: `{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7}`,
+			body:        body,
+			want:        fwkrq.Usage{PromptTokens: 11, TotalTokens: 111, CompletionTokens: 100},
+		},
+		{
+			name:        "usage header is kept when body has no usage",
+			usageHeader: `{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7}`,
+			body:        `{"object":"chat.completion","choices":[]}`,
+			want:        fwkrq.Usage{PromptTokens: 3, CompletionTokens: 4, TotalTokens: 7},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{director: &mockDirector{}}
+			reqCtx := &RequestContext{Response: &Response{Headers: make(map[string]string)}}
+			resp := &extProcPb.ProcessingRequest_ResponseHeaders{
+				ResponseHeaders: &extProcPb.HttpHeaders{
+					Headers: &configPb.HeaderMap{Headers: []*configPb.HeaderValue{
+						{Key: "content-type", RawValue: []byte("application/json")},
+						{Key: usageHeaderKey, RawValue: []byte(test.usageHeader)},
+					}},
+				},
+			}
+
+			reqCtx, err := server.HandleResponseHeaders(ctx, reqCtx, resp)
+			assert.NoError(t, err)
+
+			if test.body != "" {
+				var responseMap map[string]any
+				if err := json.Unmarshal([]byte(test.body), &responseMap); err != nil {
+					t.Fatalf("failed to unmarshal body: %v", err)
+				}
+				_, err = server.HandleResponseBody(ctx, reqCtx, responseMap)
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.want, reqCtx.Usage)
+		})
+	}
+}
+
+// FuzzExtractUsage checks that usage extraction never panics, whatever the shape of the response body.
+func TestUsageJSONRoundTrip(t *testing.T) {
+	usage := fwkrq.Usage{
+		PromptTokens:           20,
+		CompletionTokens:       1200,
+		TotalTokens:            1220,
+		PromptTokenDetails:     &fwkrq.PromptTokenDetails{CachedTokens: 10},
+		CompletionTokenDetails: &fwkrq.CompletionTokenDetails{ReasoningTokens: 1024},
+	}
+	data, err := json.Marshal(usage)
+	if err != nil {
+		t.Fatalf("failed to marshal usage: %v", err)
+	}
+	var usg map[string]any
+	if err := json.Unmarshal(data, &usg); err != nil {
+		t.Fatalf("failed to unmarshal usage: %v", err)
+	}
+	assert.Contains(t, usg, "completion_tokens_details")
+	assert.Equal(t, usage, extractUsageFields(usg, objectTypeChatCompletion))
+}
+
+func FuzzExtractUsage(f *testing.F) {
+	f.Add([]byte(`{"object":"chat.completion","usage":{"prompt_tokens":11,"completion_tokens":100,"total_tokens":111}}`))
+	f.Add([]byte(`{"object":"response","usage":{"input_tokens":1,"output_tokens":2,"output_tokens_details":{"reasoning_tokens":1}}}`))
+	f.Add([]byte(`{"usage":{"promptTokens":"11","prompt_tokens_details":{"cached_tokens":[1]}}}`))
+	f.Add([]byte(`{"usage":{"prompt_tokens":1e308,"completion_tokens":-1,"total_tokens":null}}`))
+	f.Add([]byte(`{"usage":[],"object":5}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var response map[string]any
+		if err := json.Unmarshal(data, &response); err != nil {
+			return
+		}
+		_, _ = extractUsage(response)
+	})
+}
+
+// FuzzParseRespForUsage checks that scanning streamed response chunks for usage never panics.
+func FuzzParseRespForUsage(f *testing.F) {
+	f.Add("data: {\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":10,\"total_tokens\":17}}\n\ndata: [DONE]\n\n")
+	f.Add("data: \ndata: {\ndata: [DONE]")
+	f.Add(": keep-alive\n\ndata: {\"usage\":{\"completionTokens\":3}}\n")
+	f.Add("data: {\"usage\":\"none\"}\r\n")
+
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	f.Fuzz(func(t *testing.T, responseText string) {
+		_ = parseRespForUsage(ctx, responseText)
+	})
+}
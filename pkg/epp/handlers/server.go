@@ -68,6 +68,72 @@ type Datastore interface {
 type StreamingServer struct {
 	datastore Datastore
 	director  Director
+	// logRawBodies logs request and response bodies verbatim instead of a redacted summary.
+	logRawBodies bool
+	// requestIDValidator checks the format of client-supplied request IDs. A nil validator accepts any ID.
+	requestIDValidator requtil.RequestIDValidator
+	// requestIDGenerator generates missing and replaced request IDs. A nil generator generates UUIDs.
+	requestIDGenerator requtil.RequestIDGenerator
+	// rejectInvalidRequestIDs rejects requests with an invalid request ID instead of replacing the ID.
+	rejectInvalidRequestIDs bool
+}
+
+// WithRawBodyLogging controls whether bodies are logged verbatim when processing fails. By default only a redacted
+// summary is logged, so prompt contents do not leak into the logs.
+func (s *StreamingServer) WithRawBodyLogging(enabled bool) *StreamingServer {
+	s.logRawBodies = enabled
+	return s
+}
+
+// WithRequestIDValidation checks client-supplied request IDs with the given validator. Requests with an invalid ID
+// are rejected when reject is set, otherwise the ID is replaced with one from generator, which should satisfy the
+// validator.
+func (s *StreamingServer) WithRequestIDValidation(validator requtil.RequestIDValidator, generator requtil.RequestIDGenerator, reject bool) *StreamingServer {
+	s.requestIDValidator = validator
+	s.requestIDGenerator = generator
+	s.rejectInvalidRequestIDs = reject
+	return s
+}
+
+// resolveRequestID returns the request ID to use for a request with the given client-supplied ID. A missing ID, or
+// an invalid one when invalid IDs are not rejected, is replaced with a generated ID.
+func (s *StreamingServer) resolveRequestID(requestID string) (id string, generated bool, err error) {
+	if len(requestID) > 0 && s.requestIDValidator != nil && !s.requestIDValidator(requestID) {
+		if s.rejectInvalidRequestIDs {
+			return "", false, errutil.Error{Code: errutil.BadRequest, Msg: "invalid " + requtil.RequestIdHeaderKey + " header format"}
+		}
+		requestID = ""
+	}
+	if len(requestID) == 0 {
+		if s.requestIDGenerator != nil {
+			return s.requestIDGenerator(), true, nil
+		}
+		return uuid.NewString(), true, nil
+	}
+	return requestID, false, nil
+}
+
+// loggableBody returns the body as it should appear in logs.
+func (s *StreamingServer) loggableBody(body []byte) string {
+	if s.logRawBodies {
+		return string(body)
+	}
+	return requtil.RedactBody(body)
+}
+
+// loggableRequest returns the ext_proc request as it should appear in logs. Body chunks are replaced with a redacted
+// summary unless raw body logging is enabled.
+func (s *StreamingServer) loggableRequest(req *extProcPb.ProcessingRequest) any {
+	if s.logRawBodies {
+		return req
+	}
+	switch v := req.Request.(type) {
+	case *extProcPb.ProcessingRequest_RequestBody:
+		return s.loggableBody(v.RequestBody.GetBody())
+	case *extProcPb.ProcessingRequest_ResponseBody:
+		return s.loggableBody(v.ResponseBody.GetBody())
+	}
+	return req
 }
 
 // RequestContext stores context information during the life time of an HTTP request.
@@ -86,6 +152,12 @@ type RequestContext struct {
 	ResponseCompleteTimestamp time.Time
 	RequestSize               int
 	Usage                     fwkrq.Usage
+	ResponseObjectType        string
+	ResponseFinishReason      string
+	ResponseModel             string
+	ResponseToolCallsDetected bool
+	ResponseReasoningPresent  bool
+	ModelServerError          *fwkrq.ModelServerError
 	ResponseSize              int
 	ResponseComplete          bool
 	ResponseStatusCode        string
@@ -94,11 +166,14 @@ type RequestContext struct {
 
 	SchedulingRequest *schedulingtypes.LLMRequest
 
-	RequestState         StreamRequestState
-	modelServerStreaming bool
+	RequestState           StreamRequestState
+	modelServerStreaming   bool
+	responseFramingChecked bool
 
 	Response *Response
 
+	requestHeadersToRemove []string
+
 	reqHeaderResp  *extProcPb.ProcessingResponse
 	reqBodyResp    []*extProcPb.ProcessingResponse
 	reqTrailerResp *extProcPb.ProcessingResponse
@@ -157,7 +232,6 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 	}
 
 	var body []byte
-	var responseBody map[string]any
 
 	// Create error handling var as each request should only report once for
 	// error metrics. This doesn't cover the error "Cannot receive stream request" because
@@ -204,12 +278,18 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 
 		switch v := req.Request.(type) {
 		case *extProcPb.ProcessingRequest_RequestHeaders:
-			requestID := requtil.ExtractHeaderValue(v, requtil.RequestIdHeaderKey)
+			suppliedRequestID := requtil.ExtractHeaderValue(v, requtil.RequestIdHeaderKey)
 			// request ID is a must for maintaining a state per request in plugins that hold internal state and use PluginState.
-			// if request id was not supplied as a header, we generate it ourselves.
-			if len(requestID) == 0 {
-				requestID = uuid.NewString()
-				loggerTrace.Info("RequestID header is not found in the request, generated a request id")
+			// if request id was not supplied as a header, or has an invalid format, we generate it ourselves.
+			requestID, generated, idErr := s.resolveRequestID(suppliedRequestID)
+			if idErr != nil {
+				logger.V(logutil.DEFAULT).Info("Rejecting request with an invalid request id", "requestID", suppliedRequestID)
+				err = idErr
+				break
+			}
+			if generated {
+				loggerTrace.Info("RequestID header is not found in the request or is invalid, generated a request id",
+					"suppliedRequestID", suppliedRequestID)
 				reqCtx.Request.Headers[requtil.RequestIdHeaderKey] = requestID // update in headers so director can consume it
 			}
 			logger = logger.WithValues(requtil.RequestIdHeaderKey, requestID)
@@ -217,6 +297,10 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 			ctx = log.IntoContext(ctx, logger)
 
 			err = s.HandleRequestHeaders(ctx, reqCtx, v)
+			if generated && suppliedRequestID != "" {
+				// HandleRequestHeaders copies the supplied header, which must not override its replacement.
+				reqCtx.Request.Headers[requtil.RequestIdHeaderKey] = requestID
+			}
 		case *extProcPb.ProcessingRequest_RequestBody:
 			loggerTrace.Info("Incoming body chunk", "EoS", v.RequestBody.EndOfStream)
 			// In the stream case, we can receive multiple request bodies.
@@ -225,9 +309,21 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 			// Message is buffered, we can read and decode.
 			if v.RequestBody.EndOfStream {
 				loggerTrace.Info("decoding")
+				if contentEncoding := reqCtx.Request.Headers[requtil.ContentEncodingHeaderKey]; contentEncoding != "" {
+					body, err = requtil.DecompressBody(body, contentEncoding)
+					if err != nil {
+						break
+					}
+					// The body is forwarded decoded, so the original encoding must not reach the model server.
+					delete(reqCtx.Request.Headers, requtil.ContentEncodingHeaderKey)
+					reqCtx.requestHeadersToRemove = append(reqCtx.requestHeadersToRemove, requtil.ContentEncodingHeaderKey)
+				}
+				if err = requtil.ValidateNoDuplicateKeys(body); err != nil {
+					break
+				}
 				if errUnmarshal := json.Unmarshal(body, &reqCtx.Request.Body); errUnmarshal != nil {
 					if logger.V(logutil.DEBUG).Enabled() {
-						logger.Info("Error unmarshaling request body", "body", string(body), "err", errUnmarshal)
+						logger.Info("Error unmarshaling request body", "body", s.loggableBody(body), "err", errUnmarshal)
 					}
 					err = errutil.Error{
 						Code: errutil.BadRequest,
@@ -281,7 +377,7 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 			reqCtx, responseErr = s.HandleResponseHeaders(ctx, reqCtx, v)
 			if responseErr != nil {
 				if logger.V(logutil.DEBUG).Enabled() {
-					logger.V(logutil.DEBUG).Error(responseErr, "Failed to process response headers", "request", req)
+					logger.V(logutil.DEBUG).Error(responseErr, "Failed to process response headers", "request", s.loggableRequest(req))
 				} else {
 					logger.V(logutil.DEFAULT).Error(responseErr, "Failed to process response headers")
 				}
@@ -289,67 +385,7 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 			reqCtx.respHeaderResp = s.generateResponseHeaderResponse(reqCtx)
 
 		case *extProcPb.ProcessingRequest_ResponseBody:
-			if reqCtx.modelServerStreaming {
-				// Currently we punt on response parsing if the modelServer is streaming, and we just passthrough.
-
-				responseText := string(v.ResponseBody.Body)
-				s.HandleResponseBodyModelStreaming(ctx, reqCtx, responseText)
-				if v.ResponseBody.EndOfStream {
-					loggerTrace.Info("stream completed")
-					reqCtx.ResponseComplete = true
-					if _, err := s.director.HandleResponseBodyComplete(ctx, reqCtx); err != nil {
-						logger.Error(err, "error in HandleResponseBodyComplete")
-					}
-
-					reqCtx.ResponseCompleteTimestamp = time.Now()
-					metrics.RecordRequestLatencies(ctx, reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp)
-					metrics.RecordResponseSizes(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.ResponseSize)
-					metrics.RecordNormalizedTimePerOutputToken(ctx, reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp, reqCtx.Usage.CompletionTokens)
-				}
-
-				reqCtx.respBodyResp = generateResponseBodyResponses(v.ResponseBody.Body, v.ResponseBody.EndOfStream)
-			} else {
-				body = append(body, v.ResponseBody.Body...)
-
-				// Message is buffered, we can read and decode.
-				if v.ResponseBody.EndOfStream {
-					loggerTrace.Info("stream completed")
-					// Don't send a 500 on a response error. Just let the message passthrough and log our error for debugging purposes.
-					// We assume the body is valid JSON, err messages are not guaranteed to be json, and so capturing and sending a 500 obfuscates the response message.
-					// Using the standard 'err' var will send an immediate error response back to the caller.
-					var responseErr error
-					responseErr = json.Unmarshal(body, &responseBody)
-					if responseErr != nil {
-						if logger.V(logutil.DEBUG).Enabled() {
-							logger.V(logutil.DEBUG).Error(responseErr, "Error unmarshalling request body", "body", string(body))
-						} else {
-							logger.V(logutil.DEFAULT).Error(responseErr, "Error unmarshalling request body", "body", string(body))
-						}
-						reqCtx.respBodyResp = generateResponseBodyResponses(body, true)
-						break
-					}
-
-					reqCtx, responseErr = s.HandleResponseBody(ctx, reqCtx, responseBody)
-					if responseErr != nil {
-						if logger.V(logutil.DEBUG).Enabled() {
-							logger.V(logutil.DEBUG).Error(responseErr, "Failed to process response body", "request", req)
-						} else {
-							logger.V(logutil.DEFAULT).Error(responseErr, "Failed to process response body")
-						}
-					} else if reqCtx.ResponseComplete {
-						reqCtx.ResponseCompleteTimestamp = time.Now()
-						metrics.RecordRequestLatencies(ctx, reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp)
-						metrics.RecordResponseSizes(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.ResponseSize)
-						metrics.RecordInputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.PromptTokens)
-						metrics.RecordOutputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.CompletionTokens)
-						cachedToken := 0
-						if reqCtx.Usage.PromptTokenDetails != nil {
-							cachedToken = reqCtx.Usage.PromptTokenDetails.CachedTokens
-						}
-						metrics.RecordPromptCachedTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, cachedToken)
-					}
-				}
-			}
+			body = s.processResponseBody(ctx, reqCtx, v.ResponseBody, body)
 		case *extProcPb.ProcessingRequest_ResponseTrailers:
 			// This is currently unused.
 		}
@@ -357,7 +393,7 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 		// Handle the err and fire an immediate response.
 		if err != nil {
 			if logger.V(logutil.DEBUG).Enabled() {
-				logger.V(logutil.DEBUG).Error(err, "Failed to process request", "request", req)
+				logger.V(logutil.DEBUG).Error(err, "Failed to process request", "request", s.loggableRequest(req))
 			} else {
 				logger.V(logutil.DEFAULT).Error(err, "Failed to process request")
 			}
@@ -378,6 +414,86 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 	}
 }
 
+// processResponseBody handles a chunk of the response body. Streamed responses are passed through chunk by chunk,
+// while unary responses are buffered into body, which is returned, and handled once the end of stream is reached.
+func (s *StreamingServer) processResponseBody(ctx context.Context, reqCtx *RequestContext, respBody *extProcPb.HttpBody, body []byte) []byte {
+	logger := log.FromContext(ctx)
+	loggerTrace := logger.V(logutil.TRACE)
+
+	if !reqCtx.responseFramingChecked {
+		reqCtx.reconcileResponseFraming(ctx, respBody.Body)
+	}
+	if reqCtx.modelServerStreaming {
+		// Currently we punt on response parsing if the modelServer is streaming, and we just passthrough.
+
+		responseText := string(respBody.Body)
+		s.HandleResponseBodyModelStreaming(ctx, reqCtx, responseText)
+		if respBody.EndOfStream {
+			loggerTrace.Info("stream completed")
+			reqCtx.ResponseComplete = true
+			if _, err := s.director.HandleResponseBodyComplete(ctx, reqCtx); err != nil {
+				logger.Error(err, "error in HandleResponseBodyComplete")
+			}
+
+			reqCtx.ResponseCompleteTimestamp = time.Now()
+			metrics.RecordRequestLatencies(ctx, reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp)
+			metrics.RecordResponseSizes(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.ResponseSize)
+			metrics.RecordNormalizedTimePerOutputToken(ctx, reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp, reqCtx.Usage.CompletionTokens)
+		}
+
+		reqCtx.respBodyResp = generateResponseBodyResponses(respBody.Body, respBody.EndOfStream)
+	} else {
+		body = append(body, respBody.Body...)
+
+		// Message is buffered, we can read and decode.
+		if respBody.EndOfStream {
+			loggerTrace.Info("stream completed")
+			// Don't send a 500 on a response error. Just let the message passthrough and log our error for debugging purposes.
+			// We assume the body is valid JSON, err messages are not guaranteed to be json, and so capturing and sending a 500 obfuscates the response message.
+			// Using the standard 'err' var will send an immediate error response back to the caller.
+			var responseBody map[string]any
+			responseErr := json.Unmarshal(body, &responseBody)
+			if responseErr != nil {
+				if logger.V(logutil.DEBUG).Enabled() {
+					logger.V(logutil.DEBUG).Error(responseErr, "Error unmarshalling request body", "body", s.loggableBody(body))
+				} else {
+					logger.V(logutil.DEFAULT).Error(responseErr, "Error unmarshalling request body", "body", s.loggableBody(body))
+				}
+				reqCtx.respBodyResp = generateResponseBodyResponses(body, true)
+				return body
+			}
+
+			reqCtx, responseErr = s.HandleResponseBody(ctx, reqCtx, responseBody)
+			if modelServerErr := reqCtx.ModelServerError; modelServerErr != nil {
+				// The error body was relayed to the client, so the response is otherwise handled as usual.
+				// The message may echo request content, so it is only logged at debug verbosity.
+				logger.V(logutil.DEFAULT).Info("Model server returned an error response",
+					"type", modelServerErr.Type, "code", modelServerErr.Code)
+				logger.V(logutil.DEBUG).Info("Model server error message", "message", modelServerErr.Message)
+			}
+			if responseErr != nil {
+				if logger.V(logutil.DEBUG).Enabled() {
+					logger.V(logutil.DEBUG).Error(responseErr, "Failed to process response body", "body", s.loggableBody(body))
+				} else {
+					logger.V(logutil.DEFAULT).Error(responseErr, "Failed to process response body")
+				}
+			} else if reqCtx.ResponseComplete {
+				reqCtx.ResponseCompleteTimestamp = time.Now()
+				metrics.RecordRequestLatencies(ctx, reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp)
+				metrics.RecordResponseSizes(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.ResponseSize)
+				metrics.RecordInputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.PromptTokens)
+				metrics.RecordOutputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.CompletionTokens)
+				cachedToken := 0
+				if reqCtx.Usage.PromptTokenDetails != nil {
+					cachedToken = reqCtx.Usage.PromptTokenDetails.CachedTokens
+				}
+				metrics.RecordPromptCachedTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, cachedToken)
+			}
+		}
+	}
+	return body
+}
+
 // updateStateAndSendIfNeeded checks state and can send mutiple responses in a single pass, but only if ordered properly.
 // Order of requests matter in FULL_DUPLEX_STREAMING. For both request and response, the order of response sent back MUST be: Header->Body->Trailer, with trailer being optional.
 func (r *RequestContext) updateStateAndSendIfNeeded(srv extProcPb.ExternalProcessor_ProcessServer, logger logr.Logger) error {
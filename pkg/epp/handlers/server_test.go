@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"github.com/go-logr/logr/funcr"
+	"google.golang.org/grpc"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/observability/logging"
+)
+
+// fakeProcessServer replays a fixed sequence of ext_proc requests to Process and records its responses.
+type fakeProcessServer struct {
+	grpc.ServerStream
+	ctx       context.Context
+	requests  []*extProcPb.ProcessingRequest
+	responses []*extProcPb.ProcessingResponse
+}
+
+func (f *fakeProcessServer) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeProcessServer) Recv() (*extProcPb.ProcessingRequest, error) {
+	if len(f.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := f.requests[0]
+	f.requests = f.requests[1:]
+	return req, nil
+}
+
+func (f *fakeProcessServer) Send(resp *extProcPb.ProcessingResponse) error {
+	f.responses = append(f.responses, resp)
+	return nil
+}
+
+func TestProcess_ErrorLogRedactsBody(t *testing.T) {
+	const secret = "my-secret-prompt"
+	// The duplicate key fails validation, so Process takes its error path for the body chunk.
+	requestBody := `{"model":"test","prompt":"` + secret + `","prompt":"` + secret + `"}`
+
+	tests := []struct {
+		name         string
+		logRawBodies bool
+		wantSecret   bool
+	}{
+		{
+			name: "body is redacted by default",
+		},
+		{
+			name:         "body is logged verbatim when raw body logging is enabled",
+			logRawBodies: true,
+			wantSecret:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var logs strings.Builder
+			logger := funcr.New(func(prefix, args string) {
+				logs.WriteString(args + "\n")
+			}, funcr.Options{Verbosity: logutil.DEBUG})
+			srv := &fakeProcessServer{
+				ctx: log.IntoContext(context.Background(), logger),
+				requests: []*extProcPb.ProcessingRequest{{
+					Request: &extProcPb.ProcessingRequest_RequestBody{
+						RequestBody: &extProcPb.HttpBody{Body: []byte(requestBody), EndOfStream: true},
+					},
+				}},
+			}
+
+			server := NewStreamingServer(nil, &mockDirector{}).WithRawBodyLogging(test.logRawBodies)
+			if err := server.Process(srv); err != nil {
+				t.Fatalf("Process() unexpected error: %v", err)
+			}
+			if len(srv.responses) != 1 || srv.responses[0].GetImmediateResponse() == nil {
+				t.Fatalf("Process() responses = %v, want a single immediate response", srv.responses)
+			}
+			if !strings.Contains(logs.String(), "Failed to process request") {
+				t.Fatalf("Process() did not log the request failure, logs:\n%s", logs.String())
+			}
+			if got := strings.Contains(logs.String(), secret); got != test.wantSecret {
+				t.Errorf("logs contain body content = %t, want %t, logs:\n%s", got, test.wantSecret, logs.String())
+			}
+		})
+	}
+}
@@ -300,9 +300,14 @@ func (d *Director) HandleResponseBodyStreaming(ctx context.Context, reqCtx *hand
 	logger := log.FromContext(ctx).WithValues("stage", "bodyChunk")
 	logger.V(logutil.TRACE).Info("Entering HandleResponseBodyChunk")
 	response := &fwk.Response{
-		RequestId:   reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
-		Headers:     reqCtx.Response.Headers,
-		EndOfStream: reqCtx.ResponseComplete,
+		RequestId:               reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
+		Headers:                 reqCtx.Response.Headers,
+		EndOfStream:             reqCtx.ResponseComplete,
+		ObjectType:              reqCtx.ResponseObjectType,
+		FinishReason:            reqCtx.ResponseFinishReason,
+		Model:                   reqCtx.ResponseModel,
+		ToolCallsDetected:       reqCtx.ResponseToolCallsDetected,
+		ReasoningContentPresent: reqCtx.ResponseReasoningPresent,
 	}
 
 	d.runResponseStreamingPlugins(ctx, reqCtx.SchedulingRequest, response, reqCtx.TargetPod)
@@ -315,10 +320,16 @@ func (d *Director) HandleResponseBodyComplete(ctx context.Context, reqCtx *handl
 	logger := log.FromContext(ctx).WithValues("stage", "bodyChunk")
 	logger.V(logutil.DEBUG).Info("Entering HandleResponseBodyComplete")
 	response := &fwk.Response{
-		RequestId:       reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
-		Headers:         reqCtx.Response.Headers,
-		DynamicMetadata: reqCtx.Response.DynamicMetadata,
-		Usage:           reqCtx.Usage,
+		RequestId:               reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
+		Headers:                 reqCtx.Response.Headers,
+		DynamicMetadata:         reqCtx.Response.DynamicMetadata,
+		Usage:                   reqCtx.Usage,
+		ObjectType:              reqCtx.ResponseObjectType,
+		FinishReason:            reqCtx.ResponseFinishReason,
+		Model:                   reqCtx.ResponseModel,
+		ToolCallsDetected:       reqCtx.ResponseToolCallsDetected,
+		ReasoningContentPresent: reqCtx.ResponseReasoningPresent,
+		ModelServerError:        reqCtx.ModelServerError,
 	}
 
 	d.runResponseCompletePlugins(ctx, reqCtx.SchedulingRequest, response, reqCtx.TargetPod)
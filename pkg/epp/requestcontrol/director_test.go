@@ -1152,7 +1152,10 @@ func TestDirector_HandleResponseComplete(t *testing.T) {
 		Response: &handlers.Response{
 			Headers: map[string]string{"X-Test-Complete-Header": "CompleteValue"},
 		},
-		TargetPod: &fwkdl.EndpointMetadata{NamespacedName: types.NamespacedName{Namespace: "namespace1", Name: "test-pod-name"}},
+		TargetPod:                 &fwkdl.EndpointMetadata{NamespacedName: types.NamespacedName{Namespace: "namespace1", Name: "test-pod-name"}},
+		ResponseToolCallsDetected: true,
+		ResponseReasoningPresent:  true,
+		ModelServerError:          &fwk.ModelServerError{Message: "Rate limit reached", Type: "requests", Code: "rate_limit_exceeded"},
 	}
 
 	_, err := director.HandleResponseBodyComplete(ctx, reqCtx)
@@ -1169,6 +1172,15 @@ func TestDirector_HandleResponseComplete(t *testing.T) {
 	if diff := cmp.Diff("namespace1/test-pod-name", pc1.lastTargetPodOnComplete); diff != "" {
 		t.Errorf("Scheduler.OnComplete TargetPodName mismatch (-want +got):\n%s", diff)
 	}
+	if !pc1.lastRespOnComplete.ToolCallsDetected {
+		t.Errorf("Scheduler.OnComplete ToolCallsDetected = false, want true")
+	}
+	if !pc1.lastRespOnComplete.ReasoningContentPresent {
+		t.Errorf("Scheduler.OnComplete ReasoningContentPresent = false, want true")
+	}
+	if diff := cmp.Diff(reqCtx.ModelServerError, pc1.lastRespOnComplete.ModelServerError); diff != "" {
+		t.Errorf("Scheduler.OnComplete ModelServerError mismatch (-want +got):\n%s", diff)
+	}
 }
 
 const (
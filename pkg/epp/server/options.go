@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/common/observability/logging"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
 )
 
 const (
@@ -42,8 +43,10 @@ type Options struct {
 	//
 	// ext_proc configuration.
 	//
-	GRPCPort             int  // gRPC port used for communicating with Envoy proxy. (TODO: uint16?)
-	EnableLeaderElection bool // Enables leader election for high availability
+	GRPCPort                int    // gRPC port used for communicating with Envoy proxy. (TODO: uint16?)
+	EnableLeaderElection    bool   // Enables leader election for high availability
+	RequestIDFormat         string // Required format of client-supplied x-request-id headers: any, uuid or prefix:<value>.
+	RejectInvalidRequestIDs bool   // Rejects requests with an invalid x-request-id instead of replacing it.
 	//
 	// InferencePool.
 	//
@@ -77,6 +80,7 @@ type Options struct {
 	LogVerbosity        int         // Number for the log level verbosity.
 	ZapOptions          zap.Options // Zap logging options
 	Tracing             bool        // Enables emitting traces.
+	LogRawBodies        bool        // Logs unparseable request and response bodies verbatim instead of a redacted summary.
 	HealthChecking      bool        // Enables health checking.
 	MetricsPort         int         // The metrics port exposed by EPP. (TODO: uint16)
 	GRPCHealthPort      int         // The port used for gRPC liveness and readiness probes. (TODO: uint16)
@@ -99,6 +103,7 @@ type Options struct {
 func NewOptions() *Options {
 	return &Options{ // "zero" values are no explicitly set
 		GRPCPort:                         DefaultGrpcPort,
+		RequestIDFormat:                  requtil.AnyRequestIDFormat,
 		PoolGroup:                        "inference.networking.k8s.io",
 		EndpointTargetPorts:              []int{},
 		DisableEndpointSubsetFilter:      false,
@@ -133,6 +138,11 @@ func (opts *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&opts.GRPCPort, "grpc-port", opts.GRPCPort, "gRPC port used for communicating with Envoy proxy.")
 	fs.BoolVar(&opts.EnableLeaderElection, "ha-enable-leader-election", opts.EnableLeaderElection,
 		"Enables leader election for high availability. When enabled, readiness probes will only pass on the leader.")
+	fs.StringVar(&opts.RequestIDFormat, "request-id-format", opts.RequestIDFormat,
+		"Required format of client-supplied x-request-id headers. One of 'any', 'uuid' or 'prefix:<value>'. "+
+			"Requests with an invalid id get a generated one, unless --reject-invalid-request-ids is set.")
+	fs.BoolVar(&opts.RejectInvalidRequestIDs, "reject-invalid-request-ids", opts.RejectInvalidRequestIDs,
+		"Rejects requests whose x-request-id header does not match --request-id-format instead of replacing the id.")
 	fs.StringVar(&opts.PoolGroup, "pool-group", opts.PoolGroup,
 		"Kubernetes resource group of the InferencePool this Endpoint Picker is associated with.")
 	fs.StringVar(&opts.PoolNamespace, "pool-namespace", opts.PoolNamespace,
@@ -178,6 +188,9 @@ func (opts *Options) AddFlags(fs *pflag.FlagSet) {
 	opts.ZapOptions.BindFlags(gofs) // zap expects a standard Go FlagSet and pflag.FlagSet is not compatible.
 	fs.AddGoFlagSet(gofs)
 	fs.BoolVar(&opts.Tracing, "tracing", opts.Tracing, "Enables emitting traces.")
+	fs.BoolVar(&opts.LogRawBodies, "log-raw-bodies", opts.LogRawBodies,
+		"Logs unparseable request and response bodies verbatim instead of a redacted summary. "+
+			"Bodies may contain prompts and other sensitive data, so only enable this for debugging.")
 	fs.BoolVar(&opts.HealthChecking, "health-checking", opts.HealthChecking, "Enables health checking.")
 	fs.IntVar(&opts.MetricsPort, "metrics-port", opts.MetricsPort, "The metrics port exposed by EPP.")
 	fs.IntVar(&opts.GRPCHealthPort, "grpc-health-port", opts.GRPCHealthPort,
@@ -234,6 +247,9 @@ func (opts *Options) Validate() error {
 	if opts.ConfigText != "" && opts.ConfigFile != "" {
 		return fmt.Errorf("both the %q and %q flags can not be set at the same time", "configText", "configFile")
 	}
+	if _, _, err := requtil.ParseRequestIDFormat(opts.RequestIDFormat); err != nil {
+		return fmt.Errorf("invalid %q flag: %w", "request-id-format", err)
+	}
 	if opts.ModelServerMetricsScheme != "http" && opts.ModelServerMetricsScheme != "https" {
 		return fmt.Errorf("unexpected %q value for %q flag, it can only be set to 'http' or 'https'",
 			opts.ModelServerMetricsScheme, "model-server-metrics-scheme")
@@ -43,6 +43,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector/framework/plugins/utilizationdetector"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
 )
 
 // ExtProcServerRunner provides methods to manage an external process server.
@@ -60,6 +61,9 @@ type ExtProcServerRunner struct {
 	Director                         *requestcontrol.Director
 	SaturationDetector               *utilizationdetector.Detector
 	UseExperimentalDatalayerV2       bool // Pluggable data layer feature flag
+	LogRawBodies                     bool // Logs unparseable bodies verbatim instead of a redacted summary.
+	RequestIDFormat                  string
+	RejectInvalidRequestIDs          bool
 
 	// This should only be used in tests. We won't need this once we do not inject metrics in the tests.
 	// TODO:(https://github.com/kubernetes-sigs/gateway-api-inference-extension/issues/432) Cleanup
@@ -89,6 +93,9 @@ func NewDefaultExtProcServerRunner() *ExtProcServerRunner {
 		HealthChecking:                   opts.HealthChecking,
 		RefreshPrometheusMetricsInterval: opts.RefreshPrometheusMetricsInterval,
 		MetricsStalenessThreshold:        opts.MetricsStalenessThreshold,
+		LogRawBodies:                     opts.LogRawBodies,
+		RequestIDFormat:                  opts.RequestIDFormat,
+		RejectInvalidRequestIDs:          opts.RejectInvalidRequestIDs,
 		// Dependencies can be assigned later.
 	}
 }
@@ -180,7 +187,13 @@ func (r *ExtProcServerRunner) AsRunnable(logger logr.Logger) manager.Runnable {
 			srv = grpc.NewServer()
 		}
 
-		extProcServer := handlers.NewStreamingServer(r.Datastore, r.Director)
+		requestIDValidator, requestIDGenerator, err := requtil.ParseRequestIDFormat(r.RequestIDFormat)
+		if err != nil {
+			return fmt.Errorf("failed to configure request id validation - %w", err)
+		}
+		extProcServer := handlers.NewStreamingServer(r.Datastore, r.Director).
+			WithRawBodyLogging(r.LogRawBodies).
+			WithRequestIDValidation(requestIDValidator, requestIDGenerator, r.RejectInvalidRequestIDs)
 		extProcPb.RegisterExternalProcessorServer(srv, extProcServer)
 
 		if r.HealthChecking {
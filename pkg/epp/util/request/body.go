@@ -17,7 +17,9 @@ limitations under the License.
 package request
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
@@ -73,7 +75,21 @@ func determineAPITypeFromPath(path string) string {
 }
 
 // ExtractRequestBody extracts the LLMRequestBody from the given request body map using path-based detection.
+// It must never panic: any malformed or unexpected input results in a BadRequest error.
 func ExtractRequestBody(rawBody map[string]any, headers map[string]string) (*types.LLMRequestBody, error) {
+	timeout, err := ParseRequestTimeout(headers)
+	if err != nil {
+		return nil, err
+	}
+	body, err := extractRequestBodyByAPIType(rawBody, headers)
+	if err != nil {
+		return nil, err
+	}
+	body.RequestTimeout = timeout
+	return body, nil
+}
+
+func extractRequestBodyByAPIType(rawBody map[string]any, headers map[string]string) (*types.LLMRequestBody, error) {
 	jsonBytes, err := json.Marshal(rawBody)
 	if err != nil {
 		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid request body"}
@@ -100,16 +116,17 @@ func ExtractRequestBody(rawBody map[string]any, headers map[string]string) (*typ
 
 	case chatCompletionsAPI:
 		var chatCompletions types.ChatCompletionsRequest
-		if err = json.Unmarshal(jsonBytes, &chatCompletions); err == nil {
-			if err = validateChatCompletionsMessages(chatCompletions.Messages); err == nil {
-				return &types.LLMRequestBody{ChatCompletions: &chatCompletions}, nil
-			}
+		if err = json.Unmarshal(jsonBytes, &chatCompletions); err != nil {
+			return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid chat completions request: must have valid messages field"}
 		}
-		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid chat completions request: must have valid messages field"}
+		if err = validateChatCompletionsMessages(chatCompletions.Messages); err != nil {
+			return nil, err
+		}
+		return &types.LLMRequestBody{ChatCompletions: &chatCompletions}, nil
 
 	case completionsAPI:
 		var completions types.CompletionsRequest
-		if err = json.Unmarshal(jsonBytes, &completions); err == nil && completions.Prompt != "" {
+		if err = json.Unmarshal(jsonBytes, &completions); err == nil && completionsHasPrompt(&completions) {
 			return &types.LLMRequestBody{Completions: &completions}, nil
 		}
 		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid completions request: must have prompt field"}
@@ -119,10 +136,76 @@ func ExtractRequestBody(rawBody map[string]any, headers map[string]string) (*typ
 	}
 }
 
+// ValidateNoDuplicateKeys rejects a JSON object body that repeats a top-level key. encoding/json silently keeps the
+// last value of a repeated key, while other parsers between the client and the model server may keep the first, so
+// such bodies could be interpreted differently on each hop. Bodies that are not valid JSON objects are left for the
+// caller's decoder to reject.
+func ValidateNoDuplicateKeys(body []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if tok, err := decoder.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	for decoder.More() {
+		tok, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil
+		}
+		if _, dup := seen[key]; dup {
+			return errutil.Error{Code: errutil.BadRequest, Msg: fmt.Sprintf("request body has duplicate key %q", key)}
+		}
+		seen[key] = struct{}{}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+// completionsHasPrompt reports whether a completions request carries a non-empty prompt. Every prompt of a batch
+// must be non-empty, since each is generated independently.
+func completionsHasPrompt(completions *types.CompletionsRequest) bool {
+	if completions.Prompts == nil {
+		return completions.Prompt != ""
+	}
+	for _, prompt := range completions.Prompts {
+		if prompt == "" {
+			return false
+		}
+	}
+	return len(completions.Prompts) > 0
+}
+
 func validateChatCompletionsMessages(messages []types.Message) error {
 	if len(messages) == 0 {
 		return errutil.Error{Code: errutil.BadRequest, Msg: "chat-completions request must have at least one message"}
 	}
 
-	return nil
+	for _, msg := range messages {
+		if messageHasContent(msg) {
+			return nil
+		}
+	}
+	return errutil.Error{Code: errutil.BadRequest, Msg: "chat-completions request must have at least one message with non-empty content"}
+}
+
+// messageHasContent reports whether a message carries anything for the model to act on: non-whitespace text,
+// a non-text content part (e.g. an image or audio), tool calls, or a legacy function call.
+func messageHasContent(msg types.Message) bool {
+	if len(msg.ToolCalls) > 0 || msg.FunctionCall != nil || strings.TrimSpace(msg.Content.Raw) != "" {
+		return true
+	}
+	for _, block := range msg.Content.Structured {
+		if block.Type != "text" || strings.TrimSpace(block.Text) != "" {
+			return true
+		}
+	}
+	return false
 }
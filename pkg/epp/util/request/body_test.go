@@ -17,19 +17,25 @@ limitations under the License.
 package request
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
 	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 )
 
 func TestExtractRequestData(t *testing.T) {
 	tests := []struct {
-		name    string
-		body    map[string]any
-		headers map[string]string
-		want    *types.LLMRequestBody
-		wantErr bool
+		name       string
+		body       map[string]any
+		headers    map[string]string
+		want       *types.LLMRequestBody
+		wantErr    bool
+		wantErrMsg string
 	}{
 		{
 			name:    "completions request body",
@@ -192,6 +198,67 @@ func TestExtractRequestData(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "completions with timeout header",
+			headers: map[string]string{
+				":path":                 "/v1/completions",
+				RequestTimeoutHeaderKey: "2000",
+			},
+			body: map[string]any{
+				"model":  "test",
+				"prompt": "test prompt",
+			},
+			want: &types.LLMRequestBody{
+				Completions:    &types.CompletionsRequest{Prompt: "test prompt"},
+				RequestTimeout: 2 * time.Second,
+			},
+		},
+		{
+			name: "invalid timeout header",
+			headers: map[string]string{
+				":path":                 "/v1/completions",
+				RequestTimeoutHeaderKey: "soon",
+			},
+			body: map[string]any{
+				"model":  "test",
+				"prompt": "test prompt",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "chat completions with parallel tool calls enabled",
+			headers: map[string]string{":path": "/v1/chat/completions"},
+			body: map[string]any{
+				"model":               "test",
+				"messages":            []any{map[string]any{"role": "user", "content": "hello"}},
+				"tools":               []any{map[string]any{"type": "function"}},
+				"parallel_tool_calls": true,
+			},
+			want: &types.LLMRequestBody{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages:          []types.Message{{Role: "user", Content: types.Content{Raw: "hello"}}},
+					Tools:             []any{map[string]any{"type": "function"}},
+					ParallelToolCalls: ptr.To(true),
+				},
+			},
+		},
+		{
+			name:    "chat completions with parallel tool calls disabled",
+			headers: map[string]string{":path": "/v1/chat/completions"},
+			body: map[string]any{
+				"model":               "test",
+				"messages":            []any{map[string]any{"role": "user", "content": "hello"}},
+				"tools":               []any{map[string]any{"type": "function"}},
+				"parallel_tool_calls": false,
+			},
+			want: &types.LLMRequestBody{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages:          []types.Message{{Role: "user", Content: types.Content{Raw: "hello"}}},
+					Tools:             []any{map[string]any{"type": "function"}},
+					ParallelToolCalls: ptr.To(false),
+				},
+			},
+		},
 		{
 			name:    "nil body",
 			headers: map[string]string{":path": "/v1/completions"},
@@ -231,7 +298,84 @@ func TestExtractRequestData(t *testing.T) {
 				"model":    "test",
 				"messages": []any{},
 			},
-			wantErr: true,
+			wantErr:    true,
+			wantErrMsg: "chat-completions request must have at least one message",
+		},
+		{
+			name:    "all messages have empty content",
+			headers: map[string]string{":path": "/v1/chat/completions"},
+			body: map[string]any{
+				"model": "test",
+				"messages": []any{
+					map[string]any{"role": "system", "content": ""},
+					map[string]any{"role": "user", "content": "  \n\t"},
+					map[string]any{"role": "user", "content": []any{
+						map[string]any{"type": "text", "text": " "},
+					}},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "chat-completions request must have at least one message with non-empty content",
+		},
+		{
+			name:    "assistant message with only tool calls",
+			headers: map[string]string{":path": "/v1/chat/completions"},
+			body: map[string]any{
+				"model": "test",
+				"messages": []any{
+					map[string]any{
+						"role":    "assistant",
+						"content": "",
+						"tool_calls": []any{
+							map[string]any{
+								"id":       "call_abc123",
+								"type":     "function",
+								"function": map[string]any{"name": "get_weather", "arguments": `{"city":"Paris"}`},
+							},
+						},
+					},
+				},
+			},
+			want: &types.LLMRequestBody{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages: []types.Message{
+						{
+							Role: "assistant",
+							ToolCalls: []any{
+								map[string]any{
+									"id":       "call_abc123",
+									"type":     "function",
+									"function": map[string]any{"name": "get_weather", "arguments": `{"city":"Paris"}`},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "assistant message with only a legacy function call",
+			headers: map[string]string{":path": "/v1/chat/completions"},
+			body: map[string]any{
+				"model": "test",
+				"messages": []any{
+					map[string]any{
+						"role":          "assistant",
+						"content":       nil,
+						"function_call": map[string]any{"name": "get_weather", "arguments": `{"city":"Paris"}`},
+					},
+				},
+			},
+			want: &types.LLMRequestBody{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages: []types.Message{
+						{
+							Role:         "assistant",
+							FunctionCall: map[string]any{"name": "get_weather", "arguments": `{"city":"Paris"}`},
+						},
+					},
+				},
+			},
 		},
 		{
 			name:    "message with non-string role",
@@ -354,6 +498,57 @@ func TestExtractRequestData(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "completions request with a batch of prompts",
+			headers: map[string]string{":path": "/v1/completions"},
+			body: map[string]any{
+				"model":      "test",
+				"prompt":     []any{"first prompt", "second prompt", "third prompt"},
+				"cache_salt": "salt",
+			},
+			want: &types.LLMRequestBody{
+				Completions: &types.CompletionsRequest{
+					Prompts:   []string{"first prompt", "second prompt", "third prompt"},
+					CacheSalt: "salt",
+				},
+			},
+		},
+		{
+			name:    "completions request with an empty batch of prompts",
+			headers: map[string]string{":path": "/v1/completions"},
+			body: map[string]any{
+				"model":  "test",
+				"prompt": []any{},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "completions request with a batch of empty prompts",
+			headers: map[string]string{":path": "/v1/completions"},
+			body: map[string]any{
+				"model":  "test",
+				"prompt": []any{"", ""},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "completions request with an empty prompt in a batch",
+			headers: map[string]string{":path": "/v1/completions"},
+			body: map[string]any{
+				"model":  "test",
+				"prompt": []any{"first prompt", ""},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "completions request with non-string batch prompts",
+			headers: map[string]string{":path": "/v1/completions"},
+			body: map[string]any{
+				"model":  "test",
+				"prompt": []any{"first prompt", 2},
+			},
+			wantErr: true,
+		},
 		{
 			name:    "chat completions request with cache_salt",
 			headers: map[string]string{":path": "/v1/chat/completions"},
@@ -476,6 +671,9 @@ func TestExtractRequestData(t *testing.T) {
 				return
 			}
 			if tt.wantErr {
+				if tt.wantErrMsg != "" && !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Errorf("ExtractRequestBody() error = %q, want message %q", err.Error(), tt.wantErrMsg)
+				}
 				return
 			}
 
@@ -580,3 +778,131 @@ func BenchmarkExtractRequestData_Conversations(b *testing.B) {
 		}
 	}
 }
+
+func TestCompletionsRequestBatchSize(t *testing.T) {
+	tests := []struct {
+		name string
+		body map[string]any
+		want int
+	}{
+		{
+			name: "single prompt",
+			body: map[string]any{"model": "test", "prompt": "test prompt"},
+			want: 1,
+		},
+		{
+			name: "batch of three prompts",
+			body: map[string]any{"model": "test", "prompt": []any{"a", "b", "c"}},
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractRequestBody(tt.body, map[string]string{":path": "/v1/completions"})
+			if err != nil {
+				t.Fatalf("ExtractRequestBody() unexpected error: %v", err)
+			}
+			if batchSize := got.Completions.BatchSize(); batchSize != tt.want {
+				t.Errorf("BatchSize() = %d, want %d", batchSize, tt.want)
+			}
+		})
+	}
+}
+
+// FuzzExtractRequestBody checks that ExtractRequestBody, and the accessors plugins call on its result, never panic.
+func FuzzExtractRequestBody(f *testing.F) {
+	seeds := []struct {
+		path string
+		body string
+	}{
+		{"/v1/completions", `{"model":"test","prompt":"test prompt"}`},
+		{"/v1/completions", `{"model":"test","prompt":["a","b"]}`},
+		{"/v1/completions", `{"prompt":{"nested":[1,2,3]}}`},
+		{"/v1/chat/completions", `{"model":"test","messages":[{"role":"user","content":"hello"}]}`},
+		{"/v1/chat/completions", `{"messages":[{"role":"user","content":[{"type":"text","text":"hi"},{"type":"image_url","image_url":{"url":"x"}}]}]}`},
+		{"/v1/chat/completions", `{"messages":[{"role":"assistant","tool_calls":[{"id":"1"}]}]}`},
+		{"/v1/chat/completions", `{"messages":[{"content":null}],"tools":5}`},
+		{"/v1/responses", `{"input":"hello","instructions":["a"]}`},
+		{"/v1/conversations", `{"items":[{"type":"message","content":{"a":[[[]]]}}]}`},
+		{"/v1/unknown", `[]`},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.path, []byte(seed.body))
+	}
+
+	f.Fuzz(func(t *testing.T, path string, data []byte) {
+		var rawBody map[string]any
+		if err := json.Unmarshal(data, &rawBody); err != nil {
+			return
+		}
+		body, err := ExtractRequestBody(rawBody, map[string]string{":path": path})
+		if err != nil {
+			if body != nil {
+				t.Errorf("ExtractRequestBody() returned a body along with error %v", err)
+			}
+			return
+		}
+		_ = body.CacheSalt()
+		_ = (&types.LLMRequest{Body: body}).String()
+		if _, err := EstimatePromptTokens(body); err != nil {
+			t.Errorf("EstimatePromptTokens() failed on an extracted body: %v", err)
+		}
+	})
+}
+
+func TestValidateNoDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{
+			name: "unique keys",
+			body: `{"model":"test","prompt":"hi","max_tokens":10}`,
+		},
+		{
+			name:    "duplicate top-level key",
+			body:    `{"model":"test","max_tokens":10,"prompt":"hi","max_tokens":100000}`,
+			wantErr: true,
+		},
+		{
+			name: "keys repeated in different nested objects",
+			body: `{"messages":[{"role":"user","content":"a"},{"role":"assistant","content":"b"}]}`,
+		},
+		{
+			name: "nested duplicate keys are left to the nested decoder",
+			body: `{"metadata":{"a":1,"a":2}}`,
+		},
+		{
+			name: "keys differing only in case are distinct",
+			body: `{"model":"a","Model":"b"}`,
+		},
+		{
+			name: "malformed body is left to the decoder",
+			body: `{"model":"test","prompt"`,
+		},
+		{
+			name: "non-object body is left to the decoder",
+			body: `["model","model"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNoDuplicateKeys([]byte(tt.body))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ValidateNoDuplicateKeys() expected error, got nil")
+				}
+				if code := errutil.CanonicalCode(err); code != errutil.BadRequest {
+					t.Errorf("ValidateNoDuplicateKeys() error code = %s, want %s", code, errutil.BadRequest)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ValidateNoDuplicateKeys() unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+const (
+	ContentEncodingHeaderKey = "content-encoding"
+
+	// MaxDecompressedBodySize bounds the size of a decoded request body so that a small, highly compressed payload
+	// cannot exhaust the EPP's memory.
+	MaxDecompressedBodySize = 64 << 20
+)
+
+var errBodyTooLarge = fmt.Errorf("decompressed body exceeds %d bytes", MaxDecompressedBodySize)
+
+// DecompressBody decodes a request body according to the value of its Content-Encoding header.
+// The header may list several codings, in which case they are undone in reverse order of application.
+// An empty or "identity" encoding returns the body unchanged. Unsupported codings, and bodies that decode to more than
+// MaxDecompressedBodySize bytes, return a BadRequest error.
+func DecompressBody(body []byte, contentEncoding string) ([]byte, error) {
+	codings := strings.Split(contentEncoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		var err error
+		switch coding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			body, err = decodeGzip(body)
+		case "deflate":
+			body, err = decodeDeflate(body)
+		default:
+			return nil, errutil.Error{Code: errutil.BadRequest, Msg: fmt.Sprintf("unsupported content encoding %q", coding)}
+		}
+		if err != nil {
+			return nil, errutil.Error{Code: errutil.BadRequest, Msg: fmt.Sprintf("failed to decode %s request body: %v", coding, err)}
+		}
+	}
+	return body, nil
+}
+
+func decodeGzip(body []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return readLimited(reader)
+}
+
+// decodeDeflate decodes an HTTP "deflate" body, which is specified as zlib-wrapped but is sent as raw DEFLATE by
+// some clients.
+func decodeDeflate(body []byte) ([]byte, error) {
+	if reader, err := zlib.NewReader(bytes.NewReader(body)); err == nil {
+		defer reader.Close()
+		return readLimited(reader)
+	}
+	reader := flate.NewReader(bytes.NewReader(body))
+	defer reader.Close()
+	return readLimited(reader)
+}
+
+// readLimited reads the decoded stream, failing once it grows past MaxDecompressedBodySize.
+func readLimited(reader io.Reader) ([]byte, error) {
+	decoded, err := io.ReadAll(io.LimitReader(reader, MaxDecompressedBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) > MaxDecompressedBodySize {
+		return nil, errBodyTooLarge
+	}
+	return decoded, nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+const chatCompletionsBody = `{"model":"test","messages":[{"role":"user","content":"hello"}]}`
+
+func compress(t *testing.T, newWriter func(io.Writer) io.WriteCloser, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatalf("failed to compress test body: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to compress test body: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// compressZeros compresses size zero bytes, producing a small payload with a very high decompression ratio.
+func compressZeros(t *testing.T, newWriter func(io.Writer) io.WriteCloser, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	chunk := make([]byte, 1<<20)
+	for written := 0; written < size; written += len(chunk) {
+		if _, err := w.Write(chunk[:min(len(chunk), size-written)]); err != nil {
+			t.Fatalf("failed to compress test body: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to compress test body: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompressBody(t *testing.T) {
+	gzipWriter := func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+	zlibWriter := func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }
+	flateWriter := func(w io.Writer) io.WriteCloser {
+		fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return fw
+	}
+
+	tests := []struct {
+		name            string
+		body            []byte
+		contentEncoding string
+		wantErr         bool
+	}{
+		{
+			name: "no encoding",
+			body: []byte(chatCompletionsBody),
+		},
+		{
+			name:            "identity encoding",
+			body:            []byte(chatCompletionsBody),
+			contentEncoding: "identity",
+		},
+		{
+			name:            "gzip encoding",
+			body:            compress(t, gzipWriter, chatCompletionsBody),
+			contentEncoding: "gzip",
+		},
+		{
+			name:            "gzip encoding is case-insensitive",
+			body:            compress(t, gzipWriter, chatCompletionsBody),
+			contentEncoding: "GZIP",
+		},
+		{
+			name:            "zlib-wrapped deflate encoding",
+			body:            compress(t, zlibWriter, chatCompletionsBody),
+			contentEncoding: "deflate",
+		},
+		{
+			name:            "raw deflate encoding",
+			body:            compress(t, flateWriter, chatCompletionsBody),
+			contentEncoding: "deflate",
+		},
+		{
+			name: "multiple encodings are undone in reverse order",
+			body: func() []byte {
+				return compress(t, gzipWriter, string(compress(t, zlibWriter, chatCompletionsBody)))
+			}(),
+			contentEncoding: "deflate, gzip",
+		},
+		{
+			name:            "unsupported encoding",
+			body:            []byte(chatCompletionsBody),
+			contentEncoding: "br",
+			wantErr:         true,
+		},
+		{
+			name:            "corrupt gzip body",
+			body:            []byte(chatCompletionsBody),
+			contentEncoding: "gzip",
+			wantErr:         true,
+		},
+		{
+			name:            "gzip body decoding past the size limit",
+			body:            compressZeros(t, gzipWriter, MaxDecompressedBodySize+1),
+			contentEncoding: "gzip",
+			wantErr:         true,
+		},
+		{
+			name:            "deflate body decoding past the size limit",
+			body:            compressZeros(t, zlibWriter, MaxDecompressedBodySize+1),
+			contentEncoding: "deflate",
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecompressBody(tt.body, tt.contentEncoding)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("DecompressBody() expected error, got nil")
+				}
+				if code := errutil.CanonicalCode(err); code != errutil.BadRequest {
+					t.Errorf("DecompressBody() error code = %s, want %s", code, errutil.BadRequest)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecompressBody() unexpected error: %v", err)
+			}
+
+			var rawBody map[string]any
+			if err := json.Unmarshal(got, &rawBody); err != nil {
+				t.Fatalf("decompressed body is not valid JSON: %v", err)
+			}
+			extracted, err := ExtractRequestBody(rawBody, map[string]string{":path": "/v1/chat/completions"})
+			if err != nil {
+				t.Fatalf("ExtractRequestBody() unexpected error: %v", err)
+			}
+			want := &types.LLMRequestBody{
+				ChatCompletions: &types.ChatCompletionsRequest{
+					Messages: []types.Message{{Role: "user", Content: types.Content{Raw: "hello"}}},
+				},
+			}
+			if diff := cmp.Diff(want, extracted); diff != "" {
+				t.Errorf("ExtractRequestBody() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -17,17 +17,25 @@ limitations under the License.
 package request
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 )
 
 const (
 	RequestIdHeaderKey = "x-request-id"
+	// RequestTimeoutHeaderKey carries the timeout, in milliseconds, that the client wants the request bounded by.
+	RequestTimeoutHeaderKey = "x-request-timeout-ms"
+	// MaxRequestTimeout is the largest timeout accepted in the RequestTimeoutHeaderKey header.
+	MaxRequestTimeout = time.Hour
 )
 
 var (
@@ -78,3 +86,19 @@ func ExtractHeaderValue(req *extProcPb.ProcessingRequest_RequestHeaders, headerK
 	}
 	return ""
 }
+
+// ParseRequestTimeout returns the timeout requested in the RequestTimeoutHeaderKey header, or zero if the header is
+// absent. The value must be a positive integer number of milliseconds no larger than MaxRequestTimeout, otherwise a
+// BadRequest error is returned.
+func ParseRequestTimeout(headers map[string]string) (time.Duration, error) {
+	value, ok := headers[RequestTimeoutHeaderKey]
+	if !ok {
+		return 0, nil
+	}
+	millis, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || millis <= 0 || millis > MaxRequestTimeout.Milliseconds() {
+		return 0, errutil.Error{Code: errutil.BadRequest, Msg: fmt.Sprintf("invalid %s header %q: must be a positive integer of at most %d",
+			RequestTimeoutHeaderKey, value, MaxRequestTimeout.Milliseconds())}
+	}
+	return time.Duration(millis) * time.Millisecond, nil
+}
@@ -18,9 +18,12 @@ package request
 
 import (
 	"testing"
+	"time"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 )
 
 func TestExtractHeaderValue(t *testing.T) {
@@ -135,3 +138,79 @@ func TestGetHeaderValue(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name:    "no header",
+			headers: map[string]string{},
+			want:    0,
+		},
+		{
+			name:    "valid timeout",
+			headers: map[string]string{RequestTimeoutHeaderKey: "1500"},
+			want:    1500 * time.Millisecond,
+		},
+		{
+			name:    "surrounding whitespace is ignored",
+			headers: map[string]string{RequestTimeoutHeaderKey: " 250 "},
+			want:    250 * time.Millisecond,
+		},
+		{
+			name:    "maximum timeout",
+			headers: map[string]string{RequestTimeoutHeaderKey: "3600000"},
+			want:    MaxRequestTimeout,
+		},
+		{
+			name:    "zero timeout",
+			headers: map[string]string{RequestTimeoutHeaderKey: "0"},
+			wantErr: true,
+		},
+		{
+			name:    "negative timeout",
+			headers: map[string]string{RequestTimeoutHeaderKey: "-5"},
+			wantErr: true,
+		},
+		{
+			name:    "timeout above the maximum",
+			headers: map[string]string{RequestTimeoutHeaderKey: "3600001"},
+			wantErr: true,
+		},
+		{
+			name:    "non-integer timeout",
+			headers: map[string]string{RequestTimeoutHeaderKey: "1.5s"},
+			wantErr: true,
+		},
+		{
+			name:    "empty timeout",
+			headers: map[string]string{RequestTimeoutHeaderKey: ""},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRequestTimeout(tt.headers)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ParseRequestTimeout() expected error, got nil")
+				}
+				if code := errutil.CanonicalCode(err); code != errutil.BadRequest {
+					t.Errorf("ParseRequestTimeout() error code = %s, want %s", code, errutil.BadRequest)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRequestTimeout() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRequestTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
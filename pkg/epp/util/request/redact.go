@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RedactBody returns a summary of a request or response body that is safe to log: the object type and message count
+// when the body is a JSON object that carries them, and the total length in bytes. No prompt or completion content is
+// included.
+func RedactBody(body []byte) string {
+	var summary strings.Builder
+	summary.WriteString("<redacted")
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err == nil {
+		var object string
+		if err := json.Unmarshal(fields["object"], &object); err == nil && object != "" {
+			fmt.Fprintf(&summary, " object=%q", object)
+		}
+		var messages []json.RawMessage
+		if err := json.Unmarshal(fields["messages"], &messages); err == nil && messages != nil {
+			fmt.Fprintf(&summary, " messages=%d", len(messages))
+		}
+	}
+
+	fmt.Fprintf(&summary, " length=%d>", len(body))
+	return summary.String()
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	const secret = "my SSN is 123-45-6789"
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "chat completions request",
+			body: `{"model":"test","messages":[{"role":"system","content":"be brief"},{"role":"user","content":"` + secret + `"}]}`,
+			want: "<redacted messages=2 length=118>",
+		},
+		{
+			name: "chat completion response",
+			body: `{"object":"chat.completion","choices":[{"message":{"role":"assistant","content":"` + secret + `"}}]}`,
+			want: `<redacted object="chat.completion" length=107>`,
+		},
+		{
+			name: "completions request",
+			body: `{"model":"test","prompt":"` + secret + `"}`,
+			want: "<redacted length=49>",
+		},
+		{
+			name: "malformed body",
+			body: `{"messages":[{"role":"user","content":"` + secret,
+			want: "<redacted length=60>",
+		},
+		{
+			name: "empty body",
+			body: "",
+			want: "<redacted length=0>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RedactBody([]byte(tt.body))
+			if got != tt.want {
+				t.Errorf("RedactBody() = %q, want %q", got, tt.want)
+			}
+			if strings.Contains(got, secret) {
+				t.Errorf("RedactBody() leaked message content: %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// AnyRequestIDFormat accepts any client-supplied request ID.
+	AnyRequestIDFormat = "any"
+	// UUIDRequestIDFormat requires client-supplied request IDs to be UUIDs in canonical form.
+	UUIDRequestIDFormat = "uuid"
+	// PrefixRequestIDFormat requires client-supplied request IDs to start with the value following the colon,
+	// e.g. "prefix:tenant-a-".
+	PrefixRequestIDFormat = "prefix:"
+)
+
+// RequestIDValidator reports whether a client-supplied request ID has an acceptable format.
+type RequestIDValidator func(requestID string) bool
+
+// RequestIDGenerator generates a request ID for requests that have none, or whose ID was replaced.
+type RequestIDGenerator func() string
+
+// ParseRequestIDFormat returns the validator for the given request ID format, and a generator whose IDs satisfy it.
+// The "any" format, and an empty one, return a nil validator since every ID is accepted.
+func ParseRequestIDFormat(format string) (RequestIDValidator, RequestIDGenerator, error) {
+	switch {
+	case format == "" || format == AnyRequestIDFormat:
+		return nil, uuid.NewString, nil
+	case format == UUIDRequestIDFormat:
+		return IsUUID, uuid.NewString, nil
+	case strings.HasPrefix(format, PrefixRequestIDFormat):
+		prefix := strings.TrimPrefix(format, PrefixRequestIDFormat)
+		if prefix == "" {
+			return nil, nil, fmt.Errorf("request ID format %q must specify a prefix", format)
+		}
+		validator := func(requestID string) bool { return strings.HasPrefix(requestID, prefix) }
+		generator := func() string { return prefix + uuid.NewString() }
+		return validator, generator, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported request ID format %q, must be one of %q, %q or %q<value>",
+			format, AnyRequestIDFormat, UUIDRequestIDFormat, PrefixRequestIDFormat)
+	}
+}
+
+// IsUUID reports whether the request ID is a UUID in its canonical, hyphenated form.
+func IsUUID(requestID string) bool {
+	// uuid.Parse also accepts the braced, URN and unhyphenated forms, which are not valid request IDs.
+	if len(requestID) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(requestID)
+	return err == nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"testing"
+)
+
+func TestParseRequestIDFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		wantErr     bool
+		wantNil     bool
+		acceptedIDs []string
+		rejectedIDs []string
+	}{
+		{
+			name:    "empty format accepts any id",
+			format:  "",
+			wantNil: true,
+		},
+		{
+			name:    "any format accepts any id",
+			format:  AnyRequestIDFormat,
+			wantNil: true,
+		},
+		{
+			name:        "uuid format",
+			format:      UUIDRequestIDFormat,
+			acceptedIDs: []string{"123e4567-e89b-12d3-a456-426614174000", "123E4567-E89B-12D3-A456-426614174000"},
+			rejectedIDs: []string{
+				"",
+				"test-request-id",
+				"123e4567e89b12d3a456426614174000",
+				"{123e4567-e89b-12d3-a456-426614174000}",
+				"urn:uuid:123e4567-e89b-12d3-a456-426614174000",
+				"123e4567-e89b-12d3-a456-42661417400z",
+			},
+		},
+		{
+			name:        "prefix format",
+			format:      "prefix:tenant-a-",
+			acceptedIDs: []string{"tenant-a-1", "tenant-a-"},
+			rejectedIDs: []string{"", "tenant-b-1", "x-tenant-a-1"},
+		},
+		{
+			name:    "prefix format without a prefix",
+			format:  "prefix:",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			format:  "ulid",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, generator, err := ParseRequestIDFormat(tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ParseRequestIDFormat() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRequestIDFormat() unexpected error: %v", err)
+			}
+			if generator == nil {
+				t.Fatal("ParseRequestIDFormat() returned a nil generator")
+			}
+			if tt.wantNil {
+				if validator != nil {
+					t.Error("ParseRequestIDFormat() expected a nil validator")
+				}
+				return
+			}
+			if id := generator(); !validator(id) {
+				t.Errorf("validator(%q) = false for a generated id, want true", id)
+			}
+			for _, id := range tt.acceptedIDs {
+				if !validator(id) {
+					t.Errorf("validator(%q) = false, want true", id)
+				}
+			}
+			for _, id := range tt.rejectedIDs {
+				if validator(id) {
+					t.Errorf("validator(%q) = true, want false", id)
+				}
+			}
+		})
+	}
+}
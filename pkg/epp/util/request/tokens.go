@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+const (
+	// averageCharactersPerToken is the heuristic ratio of characters to tokens for typical English text.
+	averageCharactersPerToken = 4
+)
+
+// Tokenizer counts the tokens in a piece of text.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// HeuristicTokenizer estimates token counts without a vocabulary. It takes the larger of the whitespace-separated
+// word count and the character count divided by averageCharactersPerToken, so both long words and short, dense
+// text are accounted for.
+type HeuristicTokenizer struct{}
+
+// CountTokens implements Tokenizer.
+func (HeuristicTokenizer) CountTokens(text string) int {
+	words := len(strings.Fields(text))
+	chars := (utf8.RuneCountInString(text) + averageCharactersPerToken - 1) / averageCharactersPerToken
+	return max(words, chars)
+}
+
+// EstimatePromptTokens estimates the prompt size of the request with the HeuristicTokenizer and stores it on
+// extractedBody.EstimatedPromptTokens. Building the prompt text copies the whole prompt, so the estimate is only
+// computed on demand by the plugins that use it; an estimate already stored on the body is returned as is.
+func EstimatePromptTokens(extractedBody *types.LLMRequestBody) (int, error) {
+	if extractedBody != nil && extractedBody.EstimatedPromptTokens > 0 {
+		return extractedBody.EstimatedPromptTokens, nil
+	}
+	return EstimatePromptTokensWithTokenizer(extractedBody, HeuristicTokenizer{})
+}
+
+// EstimatePromptTokensWithTokenizer estimates the prompt size of the request with the given tokenizer and stores it
+// on extractedBody.EstimatedPromptTokens.
+func EstimatePromptTokensWithTokenizer(extractedBody *types.LLMRequestBody, tokenizer Tokenizer) (int, error) {
+	if extractedBody == nil {
+		return 0, errutil.Error{Code: errutil.Internal, Msg: "cannot estimate prompt tokens of a nil request body"}
+	}
+	prompt, err := promptText(extractedBody)
+	if err != nil {
+		return 0, err
+	}
+	extractedBody.EstimatedPromptTokens = tokenizer.CountTokens(prompt)
+	return extractedBody.EstimatedPromptTokens, nil
+}
+
+// promptText combines all text the model would be prompted with into a single string.
+func promptText(body *types.LLMRequestBody) (string, error) {
+	switch {
+	case body.Completions != nil:
+		if body.Completions.Prompts != nil {
+			return strings.Join(body.Completions.Prompts, "\n"), nil
+		}
+		return body.Completions.Prompt, nil
+	case body.ChatCompletions != nil:
+		texts := make([]string, 0, len(body.ChatCompletions.Messages))
+		for _, msg := range body.ChatCompletions.Messages {
+			texts = append(texts, msg.Content.PlainText())
+		}
+		return strings.Join(texts, "\n"), nil
+	case body.Responses != nil:
+		return joinText(body.Responses.Instructions, body.Responses.Input), nil
+	case body.Conversations != nil:
+		contents := make([]any, 0, len(body.Conversations.Items))
+		for _, item := range body.Conversations.Items {
+			contents = append(contents, item.Content)
+		}
+		return joinText(contents...), nil
+	default:
+		return "", errutil.Error{Code: errutil.BadRequest, Msg: "cannot estimate prompt tokens of an empty request body"}
+	}
+}
+
+// joinText joins loosely typed prompt fields. Strings are used as is, while structured values are serialized so
+// their size still contributes to the estimate.
+func joinText(values ...any) string {
+	texts := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			texts = append(texts, v)
+		default:
+			if encoded, err := json.Marshal(v); err == nil {
+				texts = append(texts, string(encoded))
+			}
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"strings"
+	"testing"
+
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+func TestHeuristicTokenizer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{
+			name: "empty",
+			text: "",
+			want: 0,
+		},
+		{
+			name: "characters dominate",
+			text: "hello world",
+			want: 3,
+		},
+		{
+			name: "words dominate",
+			text: "a b c d e f",
+			want: 6,
+		},
+		{
+			name: "multi-byte characters are counted once",
+			text: "héllo",
+			want: 2,
+		},
+		{
+			name: "long prose",
+			text: strings.Repeat("abcd", 100),
+			want: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (HeuristicTokenizer{}).CountTokens(tt.text); got != tt.want {
+				t.Errorf("CountTokens(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+type wordTokenizer struct{}
+
+func (wordTokenizer) CountTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestEstimatePromptTokens(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      *types.LLMRequestBody
+		tokenizer Tokenizer
+		want      int
+		wantErr   bool
+	}{
+		{
+			name: "completions",
+			body: &types.LLMRequestBody{Completions: &types.CompletionsRequest{Prompt: "The quick brown fox"}},
+			want: 5,
+		},
+		{
+			name: "chat completions combine all messages",
+			body: &types.LLMRequestBody{ChatCompletions: &types.ChatCompletionsRequest{
+				Messages: []types.Message{
+					{Role: "system", Content: types.Content{Raw: "be brief"}},
+					{Role: "user", Content: types.Content{Structured: []types.ContentBlock{{Type: "text", Text: "what is two plus two"}}}},
+				},
+			}},
+			tokenizer: wordTokenizer{},
+			want:      7,
+		},
+		{
+			name: "responses include instructions",
+			body: &types.LLMRequestBody{Responses: &types.ResponsesRequest{
+				Instructions: "be brief",
+				Input:        "what is two plus two",
+			}},
+			tokenizer: wordTokenizer{},
+			want:      7,
+		},
+		{
+			name: "conversations",
+			body: &types.LLMRequestBody{Conversations: &types.ConversationsRequest{
+				Items: []types.ConversationItem{{Type: "message", Role: "user", Content: "hello there"}},
+			}},
+			tokenizer: wordTokenizer{},
+			want:      2,
+		},
+		{
+			name:    "empty body",
+			body:    &types.LLMRequestBody{},
+			wantErr: true,
+		},
+		{
+			name: "stored estimate is reused",
+			body: &types.LLMRequestBody{
+				Completions:           &types.CompletionsRequest{Prompt: "hello"},
+				EstimatedPromptTokens: 42,
+			},
+			want: 42,
+		},
+		{
+			name:    "nil body",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int
+			var err error
+			if tt.tokenizer == nil {
+				got, err = EstimatePromptTokens(tt.body)
+			} else {
+				got, err = EstimatePromptTokensWithTokenizer(tt.body, tt.tokenizer)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("EstimatePromptTokens() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EstimatePromptTokens() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EstimatePromptTokens() = %d, want %d", got, tt.want)
+			}
+			if tt.body.EstimatedPromptTokens != tt.want {
+				t.Errorf("EstimatedPromptTokens = %d, want %d", tt.body.EstimatedPromptTokens, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptText(t *testing.T) {
+	tests := []struct {
+		name string
+		body *types.LLMRequestBody
+		want string
+	}{
+		{
+			name: "text parts of one message are concatenated",
+			body: &types.LLMRequestBody{ChatCompletions: &types.ChatCompletionsRequest{
+				Messages: []types.Message{
+					{Role: "user", Content: types.Content{Structured: []types.ContentBlock{
+						{Type: "text", Text: "Describe "},
+						{Type: "image_url", ImageURL: types.ImageBlock{Url: "https://example.com/cat.png"}},
+						{Type: "text", Text: "this image."},
+					}}},
+				},
+			}},
+			want: "Describe this image.",
+		},
+		{
+			name: "separate messages are newline separated",
+			body: &types.LLMRequestBody{ChatCompletions: &types.ChatCompletionsRequest{
+				Messages: []types.Message{
+					{Role: "system", Content: types.Content{Raw: "be brief"}},
+					{Role: "user", Content: types.Content{Structured: []types.ContentBlock{
+						{Type: "text", Text: "Hello, "},
+						{Type: "text", Text: "world"},
+					}}},
+				},
+			}},
+			want: "be brief\nHello, world",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := promptText(tt.body)
+			if err != nil {
+				t.Fatalf("promptText() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("promptText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}